@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+// OrphansCmd is the Cobra command for listing directories without ws_info.toml
+var OrphansCmd = &cobra.Command{
+	Use:   "orphans",
+	Short: "List directories in the root without a ws_info.toml",
+	Long:  `Lists directories in the root that have no ws_info.toml and are therefore not workspaces yet.`,
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.OrphansCommand(cfg, args)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// AdoptCmd is the Cobra command for creating ws_info.toml in orphan directories
+var AdoptCmd = &cobra.Command{
+	Use:   "adopt [directory...]",
+	Short: "Create a ws_info.toml in directories that lack one",
+	Long: `Turns directories without a ws_info.toml into workspaces. Each gets tags inferred from
+its contents and an alias derived from its directory name. With no arguments every orphan
+directory is adopted; use --interactive to confirm, edit, or skip each one.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		err := commands.AdoptCommand(cfg, args, interactive)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	AdoptCmd.Flags().BoolP("interactive", "i", false, "Confirm each directory before adopting it")
+	rootCmd.AddCommand(OrphansCmd)
+	rootCmd.AddCommand(AdoptCmd)
+}
@@ -15,11 +15,99 @@ var AliasesCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		err := commands.ListAliasesCommand(cfg, args)
 		if err != nil {
-			log.Fatalf("Error: %v", err)
+			log.Fatal(err)
 		}
 	},
 }
 
+// AliasesInstallCmd writes the generated aliases into a shell rc file
+var AliasesInstallCmd = &cobra.Command{
+	Use:   "install [expression]",
+	Short: "Install generated aliases into a shell rc file",
+	Long: `Writes the generated aliases between marker comments in a shell rc file (default: the rc file
+of the shell given by --shell or guessed from --rc and $SHELL). Re-running replaces the block and
+leaves the rest of the file untouched; the previous version is kept as <rc>.gtm.bak.
+
+With --standalone (or --file PATH), the aliases go to a separate script such as
+~/.config/gotagmanager/aliases.zsh and the rc file only gets a line that sources it.
+
+The shell, rc file, and standalone script default to aliases.shell, aliases.rc, and aliases.file
+from the config, so each profile can install its aliases in its own place.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.InstallAliasesCommand(cfg, args, installOptions(cmd))
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// AliasesUninstallCmd removes the generated aliases from a shell rc file
+var AliasesUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Remove installed aliases from a shell rc file",
+	Long: `Removes the GoTagManager block from a shell rc file, keeping a backup as <rc>.gtm.bak.
+With --standalone or --file, the standalone alias script is deleted as well.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.UninstallAliasesCommand(installOptions(cmd))
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// AliasesConflictsCmd lists aliases declared by more than one workspace
+var AliasesConflictsCmd = &cobra.Command{
+	Use:   "conflicts",
+	Short: "List aliases declared by more than one workspace",
+	Long: `Lists every alias that more than one workspace declares, with the path of each ws_info.toml
+involved, and exits non-zero if there are any. How generated aliases settle such clashes is set by
+aliases.collision_policy in config.toml: "error", "first-wins" (default), or "suffix".`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.AliasConflictsCommand(cfg, args)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// installOptions reads the flags shared by install and uninstall. Flags that
+// are not given fall back to the [aliases] settings of the config.
+func installOptions(cmd *cobra.Command) commands.InstallAliasesOptions {
+	var opts commands.InstallAliasesOptions
+	opts.Shell, _ = cmd.Flags().GetString("shell")
+	opts.RCFile, _ = cmd.Flags().GetString("rc")
+	opts.File, _ = cmd.Flags().GetString("file")
+	opts.Standalone, _ = cmd.Flags().GetBool("standalone")
+	if !cmd.Flags().Changed("shell") {
+		opts.Shell = cfg.Aliases.Shell
+	}
+	if !cmd.Flags().Changed("rc") {
+		opts.RCFile = cfg.Aliases.RCFile
+	}
+	if !cmd.Flags().Changed("file") {
+		opts.File = cfg.Aliases.File
+	}
+	opts.AllowPathShadowing, _ = cmd.Flags().GetBool("allow-path-shadowing")
+	if track, _ := cmd.Flags().GetBool("track"); track {
+		opts.VisitCommand = selfCommandLine(cmd, "visit")
+	}
+	return opts
+}
+
 func init() {
+	for _, c := range []*cobra.Command{AliasesInstallCmd, AliasesUninstallCmd} {
+		c.Flags().StringP("shell", "s", "", "Shell syntax (default: guessed from --rc or $SHELL)")
+		c.Flags().String("rc", "", "Shell rc file (default: the shell's standard rc file)")
+		c.Flags().String("file", "", "Standalone alias script sourced from the rc file")
+		c.Flags().Bool("standalone", false, "Use ~/.config/gotagmanager/aliases.<ext> as the standalone script")
+	}
+	AliasesInstallCmd.Flags().Bool("allow-path-shadowing", false, "Allow alias names that match executables on $PATH")
+	AliasesInstallCmd.Flags().Bool("track", false, "Record a visit each time an alias is used")
+
+	AliasesCmd.AddCommand(AliasesInstallCmd)
+	AliasesCmd.AddCommand(AliasesUninstallCmd)
+	AliasesCmd.AddCommand(AliasesConflictsCmd)
 	rootCmd.AddCommand(AliasesCmd)
 }
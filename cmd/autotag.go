@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+// AutotagCmd is the Cobra command for inferring tags from workspace contents
+var AutotagCmd = &cobra.Command{
+	Use:   "autotag [workspace...]",
+	Short: "Infer tags from the files in workspaces",
+	Long: `Scans workspaces for well-known files (go.mod -> lang/go, package.json -> lang/js,
+Cargo.toml -> lang/rust, .git -> vcs/git, Dockerfile -> docker, ...) and suggests tags.
+Additional glob-to-tag mappings can be configured under [[autotag.rules]] in config.toml.
+
+With no workspaces, every workspace in the root is scanned. By default only the changes
+are reported (--dry-run); pass --apply to add the inferred tags to ws_info.toml.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apply, _ := cmd.Flags().GetBool("apply")
+		err := commands.AutotagCommand(cfg, args, apply)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	AutotagCmd.Flags().Bool("apply", false, "Write the inferred tags to ws_info.toml")
+	AutotagCmd.Flags().Bool("dry-run", false, "Only report the inferred tags (default)")
+	AutotagCmd.MarkFlagsMutuallyExclusive("apply", "dry-run")
+	rootCmd.AddCommand(AutotagCmd)
+}
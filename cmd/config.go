@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+// ConfigCmd is the parent command for inspecting and creating the config file
+var ConfigCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Create and inspect the configuration file",
+	Long: `Without --config, the configuration is read from the first of these files that exists:
+
+  $XDG_CONFIG_HOME/gotagmanager/config.toml
+  ~/.config/gotagmanager/config.toml
+  ./config.toml`,
+}
+
+// ConfigInitCmd writes a commented starter config file
+var ConfigInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a commented starter config file",
+	Long: `Writes a starter config.toml with every section commented, to --config if given or else to
+$XDG_CONFIG_HOME/gotagmanager/config.toml (default ~/.config/gotagmanager/config.toml).
+
+Run in a terminal without flags, it prompts for the workspace root, discovery depth, tag mode,
+and alias shell. For scripting, pass them as flags instead:
+
+  GoTagManager config init --root ~/src --max-depth 2 --shell fish`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noConfigAnnotation: ""},
+	Run: func(cmd *cobra.Command, args []string) {
+		var opts commands.ConfigInitOptions
+		opts.Path, _ = cmd.Flags().GetString("config")
+		opts.Force, _ = cmd.Flags().GetBool("force")
+		opts.RootDirectory, _ = cmd.Flags().GetString("root")
+		opts.MaxDepth, _ = cmd.Flags().GetInt("max-depth")
+		opts.TagMode, _ = cmd.Flags().GetString("tag-mode")
+		opts.Shell, _ = cmd.Flags().GetString("shell")
+		noPrompt, _ := cmd.Flags().GetBool("no-prompt")
+
+		// Prompt only when nothing was given on the command line and stdin is a terminal
+		flagsGiven := opts.RootDirectory != "" || opts.MaxDepth != 0 || opts.TagMode != "" || opts.Shell != ""
+		opts.Interactive = !noPrompt && !flagsGiven && stdinIsTerminal()
+
+		if err := commands.ConfigInitCommand(opts); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// ConfigShowCmd prints the effective configuration
+var ConfigShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration and where each value comes from",
+	Long: `Prints every setting after defaults, the config file, the selected profile, and environment
+variables have been merged, each followed by the layer it was taken from.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.ConfigShowCommand(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// ConfigPathCmd prints the path of the config file in use
+var ConfigPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the config file in use",
+	Long: `Prints the config file that is read, or fails if there is none. With --all, lists every location
+searched and whether it exists.`,
+	Args:        cobra.NoArgs,
+	Annotations: map[string]string{noConfigAnnotation: ""},
+	Run: func(cmd *cobra.Command, args []string) {
+		configPath, _ := cmd.Flags().GetString("config")
+		all, _ := cmd.Flags().GetBool("all")
+		err := commands.ConfigPathCommand(configPath, all)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	ConfigInitCmd.Flags().Bool("force", false, "Overwrite an existing config file")
+	ConfigInitCmd.Flags().String("root", "", "Workspace root directory (default ~/Workspace)")
+	ConfigInitCmd.Flags().Int("max-depth", 0, "Directory levels to search below the root (default 1)")
+	ConfigInitCmd.Flags().String("tag-mode", "", "Tag vocabulary mode: warn (default) or strict")
+	ConfigInitCmd.Flags().String("shell", "", "Shell for generated aliases (default: from $SHELL)")
+	ConfigInitCmd.Flags().Bool("no-prompt", false, "Never prompt; use flags and defaults only")
+	ConfigPathCmd.Flags().BoolP("all", "a", false, "List every location searched")
+
+	ConfigCmd.AddCommand(ConfigInitCmd)
+	ConfigCmd.AddCommand(ConfigShowCmd)
+	ConfigCmd.AddCommand(ConfigPathCmd)
+	rootCmd.AddCommand(ConfigCmd)
+}
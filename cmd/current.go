@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of the current command. Every other failure, including bad
+// flags and configuration errors, exits with exitError.
+const (
+	exitNotInWorkspace = 1
+	exitError          = 2
+)
+
+// CurrentCmd is the Cobra command for resolving the workspace of a directory
+var CurrentCmd = &cobra.Command{
+	Use:   "current [path]",
+	Short: "Show the workspace containing the working directory",
+	Long: `Walks up from the working directory (or the given path) to the nearest directory below the
+root that contains a ws_info.toml, and prints that workspace's name, path, tags, and aliases.
+
+--format takes a Go template over .Name, .Path, .Tags, .EffectiveTags, and .Aliases, with a
+join function, for use in shell prompts:
+
+  GoTagManager current --format '{{.Name}} [{{join .Tags ","}}]'
+
+Exit status is 0 inside a workspace, 1 outside any workspace (printing nothing), and 2 on errors.`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{quietAnnotation: "", errorExitAnnotation: strconv.Itoa(exitError)},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		err := commands.CurrentCommand(cfg, args, format)
+		if errors.Is(err, workspace.ErrNotInWorkspace) {
+			return &exitCodeError{code: exitNotInWorkspace}
+		}
+		return err
+	},
+}
+
+func init() {
+	CurrentCmd.Flags().StringP("format", "f", "", "Go template for the output, e.g. '{{.Name}}'")
+	rootCmd.AddCommand(CurrentCmd)
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+// FindCmd is the Cobra command for querying workspaces by tag
+var FindCmd = &cobra.Command{
+	Use:   "find [expression]",
+	Short: "Find workspaces matching a tag expression",
+	Long: `Lists every workspace whose tags satisfy a boolean tag expression.
+Expressions support and/or/not (or &&, ||, !), parentheses, and wildcards:
+
+  GoTagManager find "go and client and not archived"
+  GoTagManager find "(lang:* or docker) !legacy"`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.FindCommand(cfg, args)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(FindCmd)
+}
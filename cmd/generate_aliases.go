@@ -2,24 +2,47 @@ package cmd
 
 import (
 	"log"
+	"strings"
 
 	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/johnjallday/GoTagManager/internal/shell"
 	"github.com/spf13/cobra"
 )
 
 // GenerateAliasesCmd is the Cobra command for generating shell aliases
 var GenerateAliasesCmd = &cobra.Command{
-	Use:   "generate-aliases",
-	Short: "Generate shell alias commands for .zshrc",
-	Long:  `Generates alias commands based on ws_info.toml files, which can be added to your .zshrc for quick navigation.`,
+	Use:   "generate-aliases [expression]",
+	Short: "Generate shell alias commands for your shell's rc file",
+	Long: `Generates alias commands based on ws_info.toml files, which can be added to your shell's rc file
+for quick navigation. Use --shell to pick the syntax: zsh (default, or aliases.shell from the config),
+bash, fish, nushell, or powershell.
+An optional tag expression limits the output to workspaces whose effective tags match it.
+
+Alias names must be identifiers (letters, digits, '_' and '-', starting with a letter or '_') and
+must not shadow a builtin or keyword of the target shell or an executable on $PATH. Rejected aliases
+are reported on stderr and left out of the output.
+
+With --track, every alias also records a visit in the history used by jump, recent, and frequent.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		err := commands.GenerateAliasesCommand(cfg, args)
+		var opts commands.GenerateAliasesOptions
+		opts.Shell, _ = cmd.Flags().GetString("shell")
+		if !cmd.Flags().Changed("shell") && cfg.Aliases.Shell != "" {
+			opts.Shell = cfg.Aliases.Shell
+		}
+		opts.AllowPathShadowing, _ = cmd.Flags().GetBool("allow-path-shadowing")
+		if track, _ := cmd.Flags().GetBool("track"); track {
+			opts.VisitCommand = selfCommandLine(cmd, "visit")
+		}
+		err := commands.GenerateAliasesCommand(cfg, args, opts)
 		if err != nil {
-			log.Fatalf("Error: %v", err)
+			log.Fatal(err)
 		}
 	},
 }
 
 func init() {
+	GenerateAliasesCmd.Flags().Bool("allow-path-shadowing", false, "Allow alias names that match executables on $PATH")
+	GenerateAliasesCmd.Flags().Bool("track", false, "Record a visit each time an alias is used")
+	GenerateAliasesCmd.Flags().StringP("shell", "s", "zsh", "Shell syntax: "+strings.Join(shell.Names(), ", "))
 	rootCmd.AddCommand(GenerateAliasesCmd)
 }
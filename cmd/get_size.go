@@ -24,13 +24,13 @@ If no workspace is specified, it will list all available workspaces and prompt y
 			// No argument provided; prompt user to select a workspace
 			workspaceName, err = commands.SelectWorkspaceInteractive(cfg)
 			if err != nil {
-				log.Fatalf("Error selecting workspace: %v", err)
+				log.Fatalf("failed to select workspace: %v", err)
 			}
 		}
 
 		err = commands.GetSizeCommand(cfg, workspaceName)
 		if err != nil {
-			log.Fatalf("Error: %v", err)
+			log.Fatal(err)
 		}
 	},
 }
@@ -16,7 +16,7 @@ var InfoCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		err := commands.InfoCommand(cfg, args)
 		if err != nil {
-			log.Fatalf("Error: %v", err)
+			log.Fatal(err)
 		}
 	},
 }
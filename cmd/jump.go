@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"log"
+	"strings"
+
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/johnjallday/GoTagManager/internal/shell"
+	"github.com/spf13/cobra"
+)
+
+// JumpCmd is the Cobra command for fuzzy workspace navigation
+var JumpCmd = &cobra.Command{
+	Use:   "jump [query...]",
+	Short: "Print the path of the workspace best matching a fuzzy query",
+	Long: `Fuzzy-matches the query against workspace names, aliases, and tags and prints the path of the
+best match. Several terms must all match. Ties are broken by frecency, i.e. how often and how
+recently each workspace was jumped to.
+
+A program cannot change its shell's directory, so install the shell function printed by --init:
+
+  eval "$(GoTagManager jump --init zsh)"           # ~/.zshrc or ~/.bashrc (with bash)
+  GoTagManager jump --init fish | source           # ~/.config/fish/config.fish
+
+after which "j foo" cds into the best match for "foo". --init does not read the config, so a
+broken config cannot break shell startup.`,
+	Annotations: map[string]string{noConfigAnnotation: "init"},
+	Run: func(cmd *cobra.Command, args []string) {
+		if shellName, _ := cmd.Flags().GetString("init"); shellName != "" {
+			name, _ := cmd.Flags().GetString("name")
+			err := commands.JumpInitCommand(shellName, name, selfCommandLine(cmd, "jump", "--"))
+			if err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+
+		var opts commands.JumpOptions
+		opts.List, _ = cmd.Flags().GetBool("list")
+		err := commands.JumpCommand(cfg, args, opts)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	JumpCmd.Flags().String("init", "", "Print the jump shell function for a shell: "+strings.Join(shell.Names(), ", "))
+	JumpCmd.Flags().String("name", "j", "Name of the shell function printed by --init")
+	JumpCmd.Flags().BoolP("list", "l", false, "List every match with its score and frecency")
+	rootCmd.AddCommand(JumpCmd)
+}
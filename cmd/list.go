@@ -11,15 +11,18 @@ import (
 var ListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all workspaces",
-	Long:  `Lists all directories in the specified root that contain a ws_info.toml file.`,
+	Long: `Lists all directories in the specified root that contain a ws_info.toml file.
+With --sort frecency, the most used workspaces come first and each shows when it was last visited.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		err := commands.ListWorkspacesCommand(cfg, args)
+		sortBy, _ := cmd.Flags().GetString("sort")
+		err := commands.ListWorkspacesCommand(cfg, args, sortBy)
 		if err != nil {
-			log.Fatalf("Error: %v", err)
+			log.Fatal(err)
 		}
 	},
 }
 
 func init() {
+	ListCmd.Flags().StringP("sort", "s", "name", "Sort by: name, frecency")
 	rootCmd.AddCommand(ListCmd)
 }
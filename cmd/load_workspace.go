@@ -25,13 +25,13 @@ it will list all available workspaces and prompt you to select one.`,
 			// No argument provided; prompt user to select a workspace
 			workspaceName, err = commands.SelectWorkspaceInteractive(cfg)
 			if err != nil {
-				log.Fatalf("Error selecting workspace: %v", err)
+				log.Fatalf("failed to select workspace: %v", err)
 			}
 		}
 
 		err = commands.LoadWorkspaceCommand(cfg, workspaceName)
 		if err != nil {
-			log.Fatalf("Error: %v", err)
+			log.Fatal(err)
 		}
 	},
 }
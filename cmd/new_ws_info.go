@@ -1,35 +1,55 @@
 package cmd
 
 import (
-	"fmt"
 	"log"
-	"path/filepath"
+	"os"
 
-	"github.com/johnjallday/GoTagManager/internal/tag"
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
 // NewWsInfoCmd is a command that creates a new ws_info.toml for the given workspace
 var NewWsInfoCmd = &cobra.Command{
-	Use:   "new_ws_info [workspace]",
-	Short: "Create a default ws_info.toml in the specified workspace",
+	Use:   "new_ws_info [workspace] [tags...]",
+	Short: "Create a ws_info.toml in the specified workspace",
 	Long: `Creates a new ws_info.toml in the specified workspace directory if 
-it does not already exist.`,
-	Args: cobra.ExactArgs(1), // We need exactly 1 argument: workspace name
+it does not already exist.
+
+Run without flags in a terminal, it starts a wizard that prompts for tags (completing
+from tags already used across the root), aliases (checked for collisions with existing
+aliases), and accounts. For scripting, pass the values as flags instead:
+
+  GoTagManager new_ws_info site --tag client/acme --alias acme --account github=acme-bot
+
+Without explicit tags, tags are inferred from the workspace contents as with autotag.`,
+	Args: cobra.MinimumNArgs(1), // We need the workspace name, optionally followed by tags
 	Run: func(cmd *cobra.Command, args []string) {
-		workspaceName := args[0]
+		var opts commands.NewWsInfoOptions
+		opts.Tags, _ = cmd.Flags().GetStringArray("tag")
+		opts.Aliases, _ = cmd.Flags().GetStringArray("alias")
+		opts.Accounts, _ = cmd.Flags().GetStringArray("account")
+		noPrompt, _ := cmd.Flags().GetBool("no-prompt")
 
-		// Build the path to the workspace from the config root
-		workspacePath := filepath.Join(cfg.RootDirectory, workspaceName)
+		// Prompt only when nothing was given on the command line and stdin is a terminal
+		flagsGiven := len(args) > 1 || len(opts.Tags) > 0 || len(opts.Aliases) > 0 || len(opts.Accounts) > 0
+		opts.Interactive = !noPrompt && !flagsGiven && stdinIsTerminal()
 
-		if err := tag.CreateWsInfoToml(workspacePath); err != nil {
-			log.Fatalf("Error creating ws_info.toml: %v", err)
-		} else {
-			fmt.Printf("ws_info.toml successfully created/updated in %s.\n", workspacePath)
+		if err := commands.NewWsInfoCommand(cfg, args, opts); err != nil {
+			log.Fatal(err)
 		}
 	},
 }
 
+// stdinIsTerminal reports whether standard input is an interactive terminal.
+func stdinIsTerminal() bool {
+	return isatty.IsTerminal(os.Stdin.Fd())
+}
+
 func init() {
+	NewWsInfoCmd.Flags().StringArray("tag", nil, "Tag to add (repeatable)")
+	NewWsInfoCmd.Flags().StringArray("alias", nil, "Alias to add (repeatable)")
+	NewWsInfoCmd.Flags().StringArray("account", nil, "Account as key=value (repeatable)")
+	NewWsInfoCmd.Flags().Bool("no-prompt", false, "Never prompt; use flags and inferred tags only")
 	rootCmd.AddCommand(NewWsInfoCmd)
 }
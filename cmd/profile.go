@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+// ProfileCmd is the parent command for configuration profiles
+var ProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "List and inspect configuration profiles",
+	Long: `Profiles are named tables in config.toml whose settings override the top-level ones, so one
+config file can hold separate setups such as work and personal:
+
+  [profiles.work]
+  root_directory = "~/work"
+  tags.mode = "strict"
+  aliases.file = "~/.config/gotagmanager/work.zsh"
+  accounts.redact = ["*token*"]
+
+Select a profile with --profile or $GTM_PROFILE; default_profile sets the one used otherwise.`,
+}
+
+// ProfileListCmd lists the configured profiles
+var ProfileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the configured profiles",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.ProfileListCommand(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// ProfileShowCmd prints the effective settings of a profile
+var ProfileShowCmd = &cobra.Command{
+	Use:   "show [profile]",
+	Short: "Show the effective settings of a profile",
+	Long: `Shows the roots, tag vocabulary, alias output target, and account redaction rules in effect for
+the given profile, or for the active one if none is given.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := cfg
+		if len(args) > 0 {
+			var err error
+			c, err = loadConfig(cmd, args[0])
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+		err := commands.ProfileShowCommand(c)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	ProfileCmd.AddCommand(ProfileListCmd)
+	ProfileCmd.AddCommand(ProfileShowCmd)
+	rootCmd.AddCommand(ProfileCmd)
+}
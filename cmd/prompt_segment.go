@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+// PromptSegmentCmd is the Cobra command for printing a shell prompt segment
+var PromptSegmentCmd = &cobra.Command{
+	Use:   "prompt-segment [path]",
+	Short: "Print the current workspace for a shell prompt",
+	Long: `Prints a short segment describing the workspace containing the working directory (or the given
+path), and nothing outside a workspace. Workspace data is read from a cached index under the user
+cache directory, which is refreshed automatically when a ws_info.toml changes; run "index" to
+rebuild it by hand.
+
+--format takes the same template as "current --format". For example, in ~/.zshrc:
+
+  setopt prompt_subst
+  PROMPT='$(GoTagManager prompt-segment --format "{{.Name}} ")'$PROMPT
+
+or as a starship custom module:
+
+  [custom.workspace]
+  command = "GoTagManager prompt-segment"
+  when = true`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: map[string]string{quietAnnotation: ""},
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		err := commands.PromptSegmentCommand(cfg, args, format)
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+	},
+}
+
+// IndexCmd is the Cobra command for rebuilding the workspace index
+var IndexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Rebuild the workspace index used by prompt-segment",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.IndexCommand(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	PromptSegmentCmd.Flags().StringP("format", "f", "", `Go template for the segment (default "{{.Name}} [tags]")`)
+	rootCmd.AddCommand(PromptSegmentCmd)
+	rootCmd.AddCommand(IndexCmd)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+// RecentCmd is the Cobra command for listing recently visited workspaces
+var RecentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "List recently visited workspaces",
+	Long: `Lists the workspaces most recently resolved through jump, load_workspace, info, or a
+generated alias (see generate-aliases --track), newest first.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		err := commands.RecentCommand(cfg, limit)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// FrequentCmd is the Cobra command for listing the most used workspaces
+var FrequentCmd = &cobra.Command{
+	Use:   "frequent",
+	Short: "List the most used workspaces",
+	Long: `Lists visited workspaces by frecency, which weighs how often each was visited by how recently.
+The history is kept in $XDG_STATE_HOME/gotagmanager/history.json (default ~/.local/state).`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		limit, _ := cmd.Flags().GetInt("limit")
+		err := commands.FrequentCommand(cfg, limit)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// VisitCmd records a visit; generated alias hooks call it
+var VisitCmd = &cobra.Command{
+	Use:         "visit [path]",
+	Short:       "Record a visit to a workspace",
+	Args:        cobra.ExactArgs(1),
+	Hidden:      true,
+	Annotations: map[string]string{quietAnnotation: ""},
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.VisitCommand(args)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	RecentCmd.Flags().IntP("limit", "n", 10, "Number of workspaces to show (0 for all)")
+	FrequentCmd.Flags().IntP("limit", "n", 10, "Number of workspaces to show (0 for all)")
+	rootCmd.AddCommand(RecentCmd)
+	rootCmd.AddCommand(FrequentCmd)
+	rootCmd.AddCommand(VisitCmd)
+}
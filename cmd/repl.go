@@ -1,13 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/c-bata/go-prompt"
 	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/johnjallday/GoTagManager/internal/tag"
 	"github.com/johnjallday/GoTagManager/internal/workspace"
 	"github.com/spf13/cobra"
 )
@@ -53,17 +55,174 @@ func executor(input string) {
 		fmt.Println("Goodbye!")
 		os.Exit(0)
 	case "list":
-		err := commands.ListWorkspacesCommand(cfg, args[1:])
+		sortBy := "name"
+		if len(args) >= 2 {
+			sortBy = args[1]
+		}
+		err := commands.ListWorkspacesCommand(cfg, nil, sortBy)
 		if err != nil {
 			fmt.Printf("Error: %v\n", err)
 		}
 	case "aliases":
-		err := commands.ListAliasesCommand(cfg, args[1:])
+		var err error
+		if len(args) >= 2 && args[1] == "conflicts" {
+			err = commands.AliasConflictsCommand(cfg, args[2:])
+		} else {
+			err = commands.ListAliasesCommand(cfg, args[1:])
+		}
 		if err != nil {
 			fmt.Printf("Error: %v\n", err)
 		}
 	case "generate-aliases":
-		err := commands.GenerateAliasesCommand(cfg, args[1:])
+		// Accept "--shell NAME" and "--allow-path-shadowing" anywhere among the arguments
+		opts := commands.GenerateAliasesOptions{Shell: "zsh"}
+		if cfg.Aliases.Shell != "" {
+			opts.Shell = cfg.Aliases.Shell
+		}
+		var expr []string
+		for i := 1; i < len(args); i++ {
+			switch {
+			case args[i] == "--shell" && i+1 < len(args):
+				opts.Shell = args[i+1]
+				i++
+			case args[i] == "--allow-path-shadowing":
+				opts.AllowPathShadowing = true
+			default:
+				expr = append(expr, args[i])
+			}
+		}
+		err := commands.GenerateAliasesCommand(cfg, expr, opts)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case "find":
+		err := commands.FindCommand(cfg, args[1:])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case "jump":
+		// The REPL cannot change directory, so just print the best match
+		opts := commands.JumpOptions{}
+		var query []string
+		for _, arg := range args[1:] {
+			if arg == "--list" || arg == "-l" {
+				opts.List = true
+			} else {
+				query = append(query, arg)
+			}
+		}
+		err := commands.JumpCommand(cfg, query, opts)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case "tag":
+		executeTag(args[1:])
+	case "new_ws_info":
+		if len(args) < 2 {
+			fmt.Println("Usage: new_ws_info [workspace]")
+			return
+		}
+		err := commands.NewWsInfoCommand(cfg, args[1:], commands.NewWsInfoOptions{Interactive: true})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case "orphans":
+		err := commands.OrphansCommand(cfg, args[1:])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case "adopt":
+		// Confirm each directory in REPL
+		err := commands.AdoptCommand(cfg, args[1:], true)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case "autotag":
+		apply := false
+		var names []string
+		for _, arg := range args[1:] {
+			switch arg {
+			case "--apply":
+				apply = true
+			case "--dry-run":
+			default:
+				names = append(names, arg)
+			}
+		}
+		err := commands.AutotagCommand(cfg, names, apply)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case "tags":
+		sortBy, tree := "count", false
+		for _, arg := range args[1:] {
+			if arg == "tree" || arg == "--tree" {
+				tree = true
+			} else {
+				sortBy = arg
+			}
+		}
+		err := commands.TagsCommand(cfg, sortBy, true, tree)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case "current":
+		err := commands.CurrentCommand(cfg, args[1:], "")
+		if errors.Is(err, workspace.ErrNotInWorkspace) {
+			fmt.Println("Not in a workspace.")
+		} else if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case "where":
+		opts := commands.WhereOptions{}
+		var query []string
+		for _, arg := range args[1:] {
+			switch arg {
+			case "--all", "-a":
+				opts.All = true
+			case "--json":
+				opts.JSON = true
+			default:
+				query = append(query, arg)
+			}
+		}
+		err := commands.WhereCommand(cfg, query, opts)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case "index":
+		err := commands.IndexCommand(cfg)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case "profile":
+		executeProfile(args[1:])
+	case "config":
+		var err error
+		switch {
+		case len(args) >= 2 && args[1] == "show":
+			err = commands.ConfigShowCommand(cfg)
+		case len(args) >= 2 && args[1] == "path":
+			err = commands.ConfigPathCommand(cfg.File, true)
+		default:
+			fmt.Println("Usage: config show|path")
+		}
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+	case "recent", "frequent":
+		limit := 10
+		if len(args) >= 2 {
+			if n, err := strconv.Atoi(args[1]); err == nil {
+				limit = n
+			}
+		}
+		var err error
+		if command == "recent" {
+			err = commands.RecentCommand(cfg, limit)
+		} else {
+			err = commands.FrequentCommand(cfg, limit)
+		}
 		if err != nil {
 			fmt.Printf("Error: %v\n", err)
 		}
@@ -111,12 +270,101 @@ func executor(input string) {
 	}
 }
 
+// executeProfile dispatches the "profile" subcommands in REPL
+func executeProfile(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: profile list")
+		fmt.Println("       profile show [PROFILE]")
+		return
+	}
+
+	var err error
+	switch strings.ToLower(args[0]) {
+	case "list":
+		err = commands.ProfileListCommand(cfg)
+	case "show":
+		c := cfg
+		if len(args) >= 2 {
+			c, err = loadConfig(rootCmd, args[1])
+		}
+		if err == nil {
+			err = commands.ProfileShowCommand(c)
+		}
+	default:
+		err = fmt.Errorf("unknown profile subcommand: %s", args[0])
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
+// executeTag dispatches the "tag" subcommands in REPL
+func executeTag(args []string) {
+	if len(args) == 0 {
+		fmt.Println("Usage: tag add|rm|set WORKSPACE[,WORKSPACE...] [TAG...]")
+		fmt.Println("       tag rename OLD NEW [--dry-run]")
+		fmt.Println("       tag merge A B... INTO C [--dry-run]")
+		fmt.Println("       tag lint [--fix]")
+		fmt.Println("       tag rules")
+		return
+	}
+
+	// Strip the --dry-run flag so the remaining args are positional
+	dryRun := false
+	var rest []string
+	for _, arg := range args[1:] {
+		if arg == "--dry-run" {
+			dryRun = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+
+	var err error
+	switch strings.ToLower(args[0]) {
+	case "add":
+		err = commands.TagAddCommand(cfg, rest)
+	case "rm", "remove":
+		err = commands.TagRemoveCommand(cfg, rest)
+	case "set":
+		err = commands.TagSetCommand(cfg, rest)
+	case "rename":
+		err = commands.TagRenameCommand(cfg, rest, dryRun)
+	case "merge":
+		err = commands.TagMergeCommand(cfg, rest, dryRun)
+	case "lint":
+		err = commands.TagLintCommand(cfg, len(rest) > 0 && rest[0] == "--fix")
+	case "rules":
+		err = commands.TagRulesCommand(cfg)
+	default:
+		err = fmt.Errorf("unknown tag subcommand: %s", args[0])
+	}
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+	}
+}
+
 // completer provides auto-completion suggestions
 func completer(d prompt.Document) []prompt.Suggest {
 	s := []prompt.Suggest{
 		{Text: "list", Description: "List all workspaces"},
 		{Text: "aliases", Description: "List all aliases"},
 		{Text: "generate-aliases", Description: "Generate shell aliases"},
+		{Text: "find", Description: "Find workspaces matching a tag expression"},
+		{Text: "jump", Description: "Print the workspace best matching a fuzzy query"},
+		{Text: "tag", Description: "Add, remove, or set workspace tags"},
+		{Text: "tags", Description: "Show tag statistics"},
+		{Text: "autotag", Description: "Infer tags from workspace contents"},
+		{Text: "new_ws_info", Description: "Create a ws_info.toml with a wizard"},
+		{Text: "orphans", Description: "List directories without ws_info.toml"},
+		{Text: "adopt", Description: "Create ws_info.toml in orphan directories"},
+		{Text: "current", Description: "Show the workspace containing the working directory"},
+		{Text: "where", Description: "Print the path of a workspace given an alias or name"},
+		{Text: "index", Description: "Rebuild the workspace index used by prompt-segment"},
+		{Text: "config", Description: "Show the effective configuration or its file path"},
+		{Text: "profile", Description: "List and inspect configuration profiles"},
+		{Text: "recent", Description: "List recently visited workspaces"},
+		{Text: "frequent", Description: "List the most used workspaces"},
 		{Text: "info", Description: "Display workspace information"},
 		{Text: "load_workspace", Description: "Load a workspace and display its information"},
 		{Text: "get_size", Description: "Calculate and display the size of a workspace"},
@@ -125,15 +373,20 @@ func completer(d prompt.Document) []prompt.Suggest {
 		{Text: "quit", Description: "Exit the REPL"},
 	}
 
+	// Complete tags one hierarchy level at a time
+	if words, ok := tagArgumentWords(d.TextBeforeCursor()); ok {
+		return completeTags(words)
+	}
+
 	// Handle auto-completion for commands that require workspace names
 	if strings.HasPrefix(d.TextBeforeCursor(), "info ") ||
 		strings.HasPrefix(d.TextBeforeCursor(), "load_workspace ") ||
 		strings.HasPrefix(d.TextBeforeCursor(), "get_size ") {
 		// Suggest workspace names
-		workspaces, err := workspace.ListWorkspaces(cfg.RootDirectory)
+		workspaces, err := workspace.ListAll(cfg.Roots)
 		if err == nil {
 			for _, ws := range workspaces {
-				s = append(s, prompt.Suggest{Text: filepath.Base(ws), Description: "Workspace"})
+				s = append(s, prompt.Suggest{Text: ws.Name, Description: "Workspace"})
 			}
 		}
 	}
@@ -141,13 +394,80 @@ func completer(d prompt.Document) []prompt.Suggest {
 	return prompt.FilterHasPrefix(s, d.GetWordBeforeCursor(), true)
 }
 
+// tagArgumentWords reports whether the cursor is on a tag argument, i.e. after
+// "find" or after the workspace list of "tag add|rm|set".
+func tagArgumentWords(text string) ([]string, bool) {
+	words := strings.Fields(text)
+	if strings.HasSuffix(text, " ") {
+		words = append(words, "")
+	}
+	if len(words) < 2 {
+		return nil, false
+	}
+
+	switch strings.ToLower(words[0]) {
+	case "find":
+		return words, true
+	case "tag":
+		if len(words) >= 4 {
+			switch strings.ToLower(words[1]) {
+			case "add", "rm", "remove", "set":
+				return words, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// completeTags suggests the next hierarchy level for the word under the cursor.
+func completeTags(words []string) []prompt.Suggest {
+	word := words[len(words)-1]
+	// Keep query operators such as "(" and "!" in front of the completed tag
+	lead := word[:len(word)-len(strings.TrimLeft(word, "(!"))]
+
+	tags, err := commands.AllTags(cfg)
+	if err != nil {
+		return nil
+	}
+
+	var s []prompt.Suggest
+	for _, t := range tag.CompleteLevel(tags, strings.ToLower(word[len(lead):])) {
+		description := "Tag"
+		if strings.HasSuffix(t, tag.Separator) {
+			description = "Tag group"
+		}
+		s = append(s, prompt.Suggest{Text: lead + t, Description: description})
+	}
+	return s
+}
+
 // printHelp displays help information in REPL
 func printHelp() {
 	helpText := `
 Available Commands:
-  list                     List all workspaces
-  aliases                  List all aliases for each workspace
-  generate-aliases         Generate shell alias commands for .zshrc
+  list [name|frecency]     List all workspaces
+  aliases [conflicts]      List all aliases for each workspace, or only the clashing ones
+  generate-aliases [--shell NAME] [expression]  Generate shell alias commands
+  find [expression]        Find workspaces matching a tag expression
+  jump [--list] [query]    Print the path of the workspace best matching a fuzzy query
+  tag add|rm|set [workspaces] [tags]  Edit the tags of comma-separated workspaces
+  tag rename OLD NEW       Rename a tag across all workspaces (--dry-run to preview)
+  tag merge A B INTO C     Merge tags across all workspaces (--dry-run to preview)
+  tag lint [--fix]         Check all tags against the tag vocabulary
+  tag rules                List tag implication rules and report cycles
+  tags [count|name|size] [tree]  Show every tag with its workspace count and disk usage
+  autotag [workspaces] [--apply]  Infer tags from workspace contents
+  new_ws_info [workspace]  Create a ws_info.toml with a wizard
+  orphans                  List directories without ws_info.toml
+  adopt [directories]      Create ws_info.toml in orphan directories
+  current [path]           Show the workspace containing the working directory
+  where [--all] [--json] NAME  Print the path of a workspace given an alias or name
+  index                    Rebuild the workspace index used by prompt-segment
+  config show|path         Show the effective configuration or the config file in use
+  profile list             List the configuration profiles
+  profile show [profile]   Show the effective settings of a profile
+  recent [n]               List the n most recently visited workspaces
+  frequent [n]             List the n most used workspaces
   info [workspace]         Display detailed information about a workspace
   load_workspace [workspace]  Load a workspace and display its information
   get_size [workspace]     Calculate and display the size of a workspace
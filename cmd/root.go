@@ -1,12 +1,17 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strconv"
 
 	"github.com/johnjallday/GoTagManager/config"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var cfg *config.Config
@@ -19,36 +24,193 @@ var rootCmd = &cobra.Command{
 It allows you to list workspaces, view aliases, and generate shell aliases for quick navigation.`,
 	// Uncomment the following line if your bare application has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Flags and arguments are valid by now; later errors need no usage
+		cmd.SilenceUsage = true
+		_, quiet := cmd.Annotations[quietAnnotation]
+		if err := initLogging(cmd.Flags(), quiet); err != nil {
+			return err
+		}
+		return initConfig(cmd)
+	},
+	SilenceErrors: true,
 }
 
+// quietAnnotation marks commands that run on every prompt or alias use,
+// where warnings would clutter the terminal; they only log errors unless
+// --verbose is given.
+const quietAnnotation = "quiet"
+
+// noConfigAnnotation marks commands that run without loading the
+// configuration, such as those creating or locating the config file. A
+// non-empty value names a flag, and the config is only skipped when that
+// flag is set.
+const noConfigAnnotation = "noconfig"
+
+// errorExitAnnotation overrides the exit status of a command that fails,
+// for commands that reserve status 1 for a regular outcome.
+const errorExitAnnotation = "error-exit"
+
+// exitCodeError makes Execute exit with a specific status. A nil err exits
+// without logging anything.
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error { return e.err }
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	// Set up logging before cobra parses the command line, so that errors
+	// in it are logged in the requested format as well. The flags are
+	// parsed again, with the command's annotations, once it is known.
+	early := pflag.NewFlagSet(rootCmd.Name(), pflag.ContinueOnError)
+	early.ParseErrorsWhitelist.UnknownFlags = true
+	early.SetOutput(io.Discard)
+	addLogFlags(early)
+	if early.Parse(os.Args[1:]) == nil {
+		_ = initLogging(early, false)
+	}
+
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return
+	}
+
+	var exitErr *exitCodeError
+	if errors.As(err, &exitErr) {
+		if exitErr.err != nil {
+			slog.Error(exitErr.err.Error())
+		}
+		os.Exit(exitErr.code)
 	}
+	slog.Error(err.Error())
+	if code, convErr := strconv.Atoi(cmd.Annotations[errorExitAnnotation]); convErr == nil {
+		os.Exit(code)
+	}
+	os.Exit(1)
 }
 
 func init() {
 	// Define persistent flags and configuration settings.
 	rootCmd.PersistentFlags().StringP("config", "c", "", "Path to the configuration file")
-
-	// Bind flags to configuration
-	cobra.OnInitialize(initConfig)
+	rootCmd.PersistentFlags().String("profile", "", "Configuration profile to use (default: $"+config.ProfileEnv+" or default_profile)")
+	addLogFlags(rootCmd.PersistentFlags())
+	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 }
 
-func initConfig() {
-	configPath, err := rootCmd.PersistentFlags().GetString("config")
+// addLogFlags defines the flags that configure logging.
+func addLogFlags(flags *pflag.FlagSet) {
+	flags.BoolP("verbose", "v", false, "Log debug messages, such as the config file in use")
+	flags.BoolP("quiet", "q", false, "Log errors only")
+	flags.String("log-format", "text", "Log format on stderr: text or json")
+}
+
+// initLogging sends diagnostics to stderr through log/slog, at the level
+// and in the format selected by --verbose, --quiet, and --log-format.
+// quietCommand lowers the default level to errors only. Messages of the log
+// package, such as fatal errors, are logged as errors.
+func initLogging(flags *pflag.FlagSet, quietCommand bool) error {
+	level := slog.LevelInfo
+	if quietCommand {
+		level = slog.LevelError
+	}
+	if quiet, _ := flags.GetBool("quiet"); quiet {
+		level = slog.LevelError
+	}
+	if verbose, _ := flags.GetBool("verbose"); verbose {
+		level = slog.LevelDebug
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch format, _ := flags.GetString("log-format"); format {
+	case "text":
+		// Timestamps only add noise to interactive use
+		opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		}
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		return fmt.Errorf("invalid --log-format %q (expected \"text\" or \"json\")", format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	slog.SetLogLoggerLevel(slog.LevelError)
+	return nil
+}
+
+func initConfig(cmd *cobra.Command) error {
+	if flag, ok := cmd.Annotations[noConfigAnnotation]; ok && (flag == "" || cmd.Flags().Changed(flag)) {
+		return nil
+	}
+
+	profile, err := cmd.Flags().GetString("profile")
 	if err != nil {
-		log.Fatalf("Error reading config flag: %v", err)
+		return fmt.Errorf("error reading profile flag: %w", err)
 	}
 
-	cfg, err = config.LoadConfig(configPath)
+	cfg, err = loadConfig(cmd, profile)
 	if err != nil {
-		log.Fatalf("Error loading configuration: %v", err)
+		return fmt.Errorf("error loading configuration: %w", err)
+	}
+	return nil
+}
+
+// loadConfig loads the configuration file given by the config flag with
+// the named profile applied.
+func loadConfig(cmd *cobra.Command, profile string) (*config.Config, error) {
+	configPath, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return nil, fmt.Errorf("error reading config flag: %w", err)
+	}
+	return config.LoadConfig(configPath, profile)
+}
+
+// selfCommandLine is the command line that runs this binary with the same
+// config file and profile and the given arguments, for use in generated shell code.
+// Without a loaded config, the file and profile are taken from the flags.
+func selfCommandLine(cmd *cobra.Command, args ...string) []string {
+	binary, err := os.Executable()
+	if err != nil {
+		binary = os.Args[0]
+	}
+	command := []string{binary}
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath == "" && cfg != nil {
+		configPath = cfg.File
+	} else if configPath == "" {
+		configPath = config.FindFile("")
+	}
+	if configPath != "" {
+		if abs, err := filepath.Abs(configPath); err == nil {
+			configPath = abs
+		}
+		command = append(command, "--config", configPath)
+	}
+	profile, _ := cmd.Flags().GetString("profile")
+	if cfg != nil {
+		profile = cfg.Profile
+	}
+	if profile != "" {
+		command = append(command, "--profile", profile)
 	}
+	return append(command, args...)
 }
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+// TagCmd is the parent Cobra command for editing workspace tags
+var TagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Add, remove, set, rename, or merge workspace tags",
+	Long: `Edits the [info].tags list in ws_info.toml without hand-editing TOML.
+Workspaces are given as a comma-separated list, so one command can update several at once:
+
+  GoTagManager tag add site,api client go
+  GoTagManager tag rm site 'lang:*'
+  GoTagManager tag set api go archived
+  GoTagManager tag rename golang go --dry-run
+  GoTagManager tag merge js javascript INTO lang:js`,
+}
+
+// TagAddCmd adds tags to workspaces
+var TagAddCmd = &cobra.Command{
+	Use:   "add WORKSPACE[,WORKSPACE...] TAG...",
+	Short: "Add tags to one or more workspaces",
+	Args:  cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.TagAddCommand(cfg, args)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// TagRemoveCmd removes tags from workspaces
+var TagRemoveCmd = &cobra.Command{
+	Use:     "rm WORKSPACE[,WORKSPACE...] TAG...",
+	Aliases: []string{"remove"},
+	Short:   "Remove tags from one or more workspaces",
+	Long:    `Removes tags from the given workspaces. Tags may be wildcard patterns such as 'lang:*'.`,
+	Args:    cobra.MinimumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.TagRemoveCommand(cfg, args)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// TagSetCmd replaces the tags of workspaces
+var TagSetCmd = &cobra.Command{
+	Use:   "set WORKSPACE[,WORKSPACE...] [TAG...]",
+	Short: "Replace the tags of one or more workspaces",
+	Long:  `Replaces the tags of the given workspaces. With no tags, the tag list is cleared.`,
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.TagSetCommand(cfg, args)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// TagRenameCmd renames a tag across all workspaces
+var TagRenameCmd = &cobra.Command{
+	Use:   "rename OLD NEW",
+	Short: "Rename a tag across all workspaces",
+	Long: `Renames a tag in every workspace under the root and reports each change.
+No file is written if any ws_info.toml fails to parse.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		err := commands.TagRenameCommand(cfg, args, dryRun)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// TagMergeCmd merges several tags into one across all workspaces
+var TagMergeCmd = &cobra.Command{
+	Use:   "merge A B... INTO C",
+	Short: "Merge several tags into one across all workspaces",
+	Long: `Replaces each of the source tags with the target tag in every workspace under the root
+and reports each change. No file is written if any ws_info.toml fails to parse.`,
+	Args: cobra.MinimumNArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		err := commands.TagMergeCommand(cfg, args, dryRun)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// TagLintCmd checks workspace tags against the tag vocabulary
+var TagLintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check all workspace tags against the tag vocabulary",
+	Long: `Reports tags that are not normalized, are synonyms of a canonical tag, or are not in
+the tag vocabulary configured under [tags] in config.toml. With --fix, synonyms and
+unnormalized tags are rewritten to their canonical form.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fix, _ := cmd.Flags().GetBool("fix")
+		err := commands.TagLintCommand(cfg, fix)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// TagRulesCmd lists the tag implication rules
+var TagRulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "List tag implication rules and report cycles",
+	Long: `Lists the [[tags.implications]] rules from config.toml and reports any cycles among them.
+Implied tags are added to a workspace's effective tags, which find, tags, and generate-aliases use;
+they are never written to ws_info.toml.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := commands.TagRulesCommand(cfg)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	TagRenameCmd.Flags().Bool("dry-run", false, "Report the changes without writing any file")
+	TagMergeCmd.Flags().Bool("dry-run", false, "Report the changes without writing any file")
+
+	TagLintCmd.Flags().Bool("fix", false, "Rewrite synonyms and unnormalized tags to their canonical form")
+
+	TagCmd.AddCommand(TagRenameCmd)
+	TagCmd.AddCommand(TagLintCmd)
+	TagCmd.AddCommand(TagRulesCmd)
+	TagCmd.AddCommand(TagMergeCmd)
+	TagCmd.AddCommand(TagAddCmd)
+	TagCmd.AddCommand(TagRemoveCmd)
+	TagCmd.AddCommand(TagSetCmd)
+	rootCmd.AddCommand(TagCmd)
+}
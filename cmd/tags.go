@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+// TagsCmd is the Cobra command for the tag statistics report
+var TagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Show every tag with its workspace count and disk usage",
+	Long: `Aggregates the tags of all workspaces and prints each tag with the number of workspaces
+using it, their total disk size, and the workspace names. Use --sort to order by count, name, or size.
+With --tree, hierarchical tags such as client/acme are shown as a tree whose counts include nested tags.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		sortBy, _ := cmd.Flags().GetString("sort")
+		noSize, _ := cmd.Flags().GetBool("no-size")
+		tree, _ := cmd.Flags().GetBool("tree")
+		err := commands.TagsCommand(cfg, sortBy, !noSize, tree)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	TagsCmd.Flags().StringP("sort", "s", "count", "Sort order: count, name, or size")
+	TagsCmd.Flags().Bool("no-size", false, "Skip calculating disk usage")
+	TagsCmd.Flags().Bool("tree", false, "Show hierarchical tags as a tree")
+	rootCmd.AddCommand(TagsCmd)
+}
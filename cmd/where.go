@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/johnjallday/GoTagManager/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+// WhereCmd is the Cobra command for resolving an alias or name to a path
+var WhereCmd = &cobra.Command{
+	Use:   "where [alias-or-name]",
+	Short: "Print the path of a workspace given an alias or name",
+	Long: `Prints the absolute path of the workspace with the given alias, exact name, or unique name
+prefix, tried in that order. It never prompts, so scripts can use it instead of sourcing the
+generated aliases:
+
+  cd "$(GoTagManager where myalias)"
+
+An ambiguous prefix is an error unless --all is given, which prints every match. --json prints
+the name, path, and kind of match as a JSON object (or an array with --all).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var opts commands.WhereOptions
+		opts.All, _ = cmd.Flags().GetBool("all")
+		opts.JSON, _ = cmd.Flags().GetBool("json")
+		err := commands.WhereCommand(cfg, args, opts)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	WhereCmd.Flags().BoolP("all", "a", false, "Print every match instead of failing on ambiguous queries")
+	WhereCmd.Flags().Bool("json", false, "Print matches as JSON")
+	rootCmd.AddCommand(WhereCmd)
+}
@@ -2,47 +2,250 @@ package config
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/johnjallday/GoTagManager/internal/fileutil"
 	"github.com/spf13/viper"
 )
 
 // Config holds the configuration settings.
 type Config struct {
-	RootDirectory string `mapstructure:"root_directory"`
+	RootDirectory string      `mapstructure:"root_directory"`
+	Roots         []Root      `mapstructure:"roots"`
+	Tags          TagRegistry `mapstructure:"tags"`
+	AutoTag       AutoTag     `mapstructure:"autotag"`
+	Aliases       Aliases     `mapstructure:"aliases"`
+	Discovery     Discovery   `mapstructure:"discovery"`
+	Accounts      Accounts    `mapstructure:"accounts"`
+
+	// Profile is the name of the active profile, or empty if none is
+	// selected. Profiles lists every profile in the config file.
+	Profile  string   `mapstructure:"-"`
+	Profiles []string `mapstructure:"-"`
+
+	// File is the config file that was read, or empty if none was found.
+	// Settings lists every value read with the place it came from.
+	File     string    `mapstructure:"-"`
+	Settings []Setting `mapstructure:"-"`
 }
 
-// LoadConfig initializes Viper, reads the config file, and environment variables.
-func LoadConfig(configPath string) (*Config, error) {
-	v := viper.New()
+// Root is one directory that workspaces are discovered in. Several can be
+// configured instead of root_directory:
+//
+//	[[roots]]
+//	label = "work"
+//	path = "~/work"
+//	max_depth = 2
+//	exclude = ["archive"]
+//
+// Discovery settings a root leaves out fall back to [discovery]. The label
+// defaults to the last element of the path and qualifies workspace names
+// that occur in more than one root, as in "work:site".
+type Root struct {
+	Label     string `mapstructure:"label"`
+	Path      string `mapstructure:"path"`
+	Discovery `mapstructure:",squash"`
+}
 
-	if configPath != "" {
-		// Use the specified config file
-		v.SetConfigFile(configPath)
-	} else {
-		// Look for config.toml in the current directory
-		v.SetConfigName("config") // name of config file (without extension)
-		v.SetConfigType("toml")   // REQUIRED if the config file does not have the extension in the name
-		v.AddConfigPath(".")      // look for config in the current directory
+// Discovery configures how workspaces are found below the root:
+//
+//	[discovery]
+//	max_depth = 3    # directory levels below the root to search; 1 = direct children only
+//	nested = false   # also search inside workspaces for nested workspaces
+//	exclude = [".git", "node_modules", "__pycache__"]
+//
+// Exclude patterns are globs (in path.Match syntax) matched against
+// directory names, or against the slash-separated path relative to the root
+// if they contain a '/'. Hidden directories are always skipped. Nested is a
+// pointer so that a root can turn off nesting enabled in [discovery].
+type Discovery struct {
+	MaxDepth int      `mapstructure:"max_depth"`
+	Nested   *bool    `mapstructure:"nested"`
+	Exclude  []string `mapstructure:"exclude"`
+}
+
+// IsNested reports whether nested workspaces are searched for.
+func (d Discovery) IsNested() bool {
+	return d.Nested != nil && *d.Nested
+}
+
+// Aliases configures alias handling:
+//
+//	[aliases]
+//	collision_policy = "first-wins" # or "error", "suffix"
+//	shell = "zsh"                   # default shell for generated aliases
+//	rc = "~/.zshrc"                 # rc file for aliases install
+//	file = "~/.config/gotagmanager/work.zsh" # standalone script for aliases install
+//
+// Shell, RCFile and File are defaults that command-line flags override.
+type Aliases struct {
+	CollisionPolicy string `mapstructure:"collision_policy"`
+	Shell           string `mapstructure:"shell"`
+	RCFile          string `mapstructure:"rc"`
+	File            string `mapstructure:"file"`
+}
+
+// Accounts configures how workspace accounts are displayed:
+//
+//	[accounts]
+//	redact = ["*token*", "*password*"]
+//
+// The values of accounts whose key matches a Redact glob (case-insensitive)
+// are hidden in command output.
+type Accounts struct {
+	Redact []string `mapstructure:"redact"`
+}
+
+// Redacted reports whether the value of the account key should be hidden.
+func (a Accounts) Redacted(key string) bool {
+	key = strings.ToLower(key)
+	for _, pattern := range a.Redact {
+		if ok, _ := filepath.Match(strings.ToLower(pattern), key); ok {
+			return true
+		}
 	}
+	return false
+}
+
+// TagRegistry is the optional controlled tag vocabulary, configured as:
+//
+//	[tags]
+//	mode = "strict" # or "warn"
+//
+//	[[tags.vocabulary]]
+//	name = "go"
+//	description = "Go projects"
+//	synonyms = ["golang"]
+//
+//	[[tags.implications]]
+//	if = "client/*"
+//	then = ["billable"]
+//
+// An empty vocabulary allows any tag.
+type TagRegistry struct {
+	Mode         string           `mapstructure:"mode"`
+	Vocabulary   []TagDefinition  `mapstructure:"vocabulary"`
+	Implications []TagImplication `mapstructure:"implications"`
+}
+
+// TagDefinition describes one allowed tag and the synonyms that normalize to it.
+type TagDefinition struct {
+	Name        string   `mapstructure:"name"`
+	Description string   `mapstructure:"description"`
+	Synonyms    []string `mapstructure:"synonyms"`
+}
+
+// TagImplication declares that any tag matching If (a tag or wildcard
+// pattern) implies every tag in Then.
+type TagImplication struct {
+	If   string   `mapstructure:"if"`
+	Then []string `mapstructure:"then"`
+}
+
+// AutoTag configures tag inference from workspace contents:
+//
+//	[autotag]
+//	disable_defaults = false
+//
+//	[[autotag.rules]]
+//	glob = "*.ipynb"
+//	tags = ["jupyter"]
+type AutoTag struct {
+	DisableDefaults bool          `mapstructure:"disable_defaults"`
+	Rules           []AutoTagRule `mapstructure:"rules"`
+}
+
+// AutoTagRule suggests Tags for any workspace containing a path matching Glob.
+type AutoTagRule struct {
+	Glob string   `mapstructure:"glob"`
+	Tags []string `mapstructure:"tags"`
+}
+
+// DefaultRootDirectory is the workspace root used when neither the config
+// file nor WORKSPACE_ROOT sets one.
+const DefaultRootDirectory = "~/Workspace"
+
+// Tag registry modes.
+const (
+	TagModeWarn   = "warn"
+	TagModeStrict = "strict"
+)
+
+// ProfileEnv names the environment variable that selects a profile when
+// none is given on the command line.
+const ProfileEnv = "GTM_PROFILE"
+
+// LoadConfig initializes Viper, reads the config file, and environment variables.
+//
+// Profiles are tables in the config file whose settings override the top
+// level ones:
+//
+//	default_profile = "personal"
+//
+//	[profiles.work]
+//	root_directory = "~/work"
+//	aliases.file = "~/.config/gotagmanager/work.zsh"
+//	accounts.redact = ["*"]
+//
+// The profile argument selects one; if it is empty, $GTM_PROFILE and then
+// default_profile are used.
+func LoadConfig(configPath string, profile string) (*Config, error) {
+	v := viper.New()
 
 	// Set default values
-	v.SetDefault("root_directory", "/Users/jj/Workspace/")
+	v.SetDefault("root_directory", DefaultRootDirectory)
+	v.SetDefault("tags.mode", TagModeWarn)
+	v.SetDefault("aliases.collision_policy", "first-wins")
+	v.SetDefault("discovery.max_depth", 1)
+	v.SetDefault("discovery.nested", false)
+	v.SetDefault("discovery.exclude", []string{".git", "node_modules", "__pycache__"})
 
 	// Bind environment variables
 	v.AutomaticEnv() // read in environment variables that match
 
-	// Read the config file
-	if err := v.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			// Config file not found; proceed with defaults and environment variables
-			fmt.Println("Config file not found; using default values and environment variables.")
-		} else {
-			// Config file was found but another error was produced
+	// Read the config file, looking in the XDG config directory, ~/.config,
+	// and the current directory unless a path is given
+	file := FindFile(configPath)
+	if file == "" {
+		// Config file not found; proceed with defaults and environment variables
+		slog.Debug("config file not found; using default values and environment variables", "searched", SearchPaths())
+	} else {
+		v.SetConfigFile(file)
+		v.SetConfigType("toml")
+		if err := v.ReadInConfig(); err != nil {
 			return nil, fmt.Errorf("fatal error config file: %w", err)
 		}
-	} else {
-		fmt.Println("Using config file:", v.ConfigFileUsed())
+		slog.Debug("using config file", "path", v.ConfigFileUsed())
+	}
+
+	// Apply the selected profile
+	profiles := v.GetStringMap("profiles")
+	profileSource := SourceCommandLine
+	if profile == "" {
+		profile, profileSource = os.Getenv(ProfileEnv), "env "+ProfileEnv
+	}
+	if profile == "" {
+		profile, profileSource = v.GetString("default_profile"), SourceFile
+	}
+	var profileSettings *viper.Viper
+	if profile != "" {
+		profile = strings.ToLower(profile)
+		if _, ok := profiles[profile]; !ok {
+			return nil, fmt.Errorf("unknown profile %q (available: %s)", profile, strings.Join(sortedKeys(profiles), ", "))
+		}
+		profileSettings = v.Sub("profiles." + profile)
+		if profileSettings == nil {
+			return nil, fmt.Errorf("profile %q must be a table", profile)
+		}
+		if err := v.MergeConfigMap(profileSettings.AllSettings()); err != nil {
+			return nil, fmt.Errorf("error applying profile %q: %w", profile, err)
+		}
+		slog.Debug("using profile", "profile", profile, "source", profileSource)
 	}
 
 	// Bind specific environment variables to config fields
@@ -56,12 +259,142 @@ func LoadConfig(configPath string) (*Config, error) {
 	if err := v.Unmarshal(&cfg); err != nil {
 		return nil, fmt.Errorf("unable to decode into struct: %w", err)
 	}
+	cfg.Profile = profile
+	cfg.Profiles = sortedKeys(profiles)
+	cfg.File = file
+	cfg.Settings = collectSettings(v, profileSettings, profile)
+	if profile != "" {
+		cfg.Settings = append([]Setting{{Key: "profile", Value: profile, Source: profileSource}}, cfg.Settings...)
+	}
+	// A profile that sets only root_directory replaces the top-level roots.
+	if profileSettings != nil && profileSettings.IsSet("root_directory") && !profileSettings.IsSet("roots") {
+		cfg.Roots = nil
+	}
 
-	// Validate the root directory
-	if _, err := os.Stat(cfg.RootDirectory); os.IsNotExist(err) {
-		return nil, fmt.Errorf("root directory does not exist: %s", cfg.RootDirectory)
+	// Validate workspace discovery
+	if cfg.Discovery.MaxDepth < 1 {
+		return nil, fmt.Errorf("invalid discovery.max_depth %d (must be at least 1)", cfg.Discovery.MaxDepth)
+	}
+	for _, pattern := range cfg.Discovery.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid discovery exclude pattern %q: %w", pattern, err)
+		}
+	}
+
+	// Validate the root directories
+	if err := resolveRoots(&cfg); err != nil {
+		if file == "" {
+			return nil, fmt.Errorf("%w; run 'config init' to create %s", err, DefaultPath())
+		}
+		return nil, err
+	}
+	// Validate the alias collision policy
+	switch cfg.Aliases.CollisionPolicy {
+	case "error", "first-wins", "suffix":
+	default:
+		return nil, fmt.Errorf("invalid aliases.collision_policy %q (expected \"error\", \"first-wins\", or \"suffix\")", cfg.Aliases.CollisionPolicy)
+	}
+
+	// Validate the tag registry
+	if cfg.Tags.Mode != TagModeWarn && cfg.Tags.Mode != TagModeStrict {
+		return nil, fmt.Errorf("invalid tags.mode %q (expected %q or %q)", cfg.Tags.Mode, TagModeWarn, TagModeStrict)
+	}
+	for _, def := range cfg.Tags.Vocabulary {
+		if def.Name == "" {
+			return nil, fmt.Errorf("tags.vocabulary entry without a name")
+		}
+	}
+	for _, rule := range cfg.Tags.Implications {
+		if rule.If == "" || len(rule.Then) == 0 {
+			return nil, fmt.Errorf("tags.implications entry needs both 'if' and 'then'")
+		}
+	}
+	for _, rule := range cfg.AutoTag.Rules {
+		if rule.Glob == "" || len(rule.Tags) == 0 {
+			return nil, fmt.Errorf("autotag.rules entry needs both 'glob' and 'tags'")
+		}
+		if _, err := filepath.Match(rule.Glob, ""); err != nil {
+			return nil, fmt.Errorf("invalid autotag glob %q: %w", rule.Glob, err)
+		}
+	}
+	for _, pattern := range cfg.Accounts.Redact {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid accounts redact pattern %q: %w", pattern, err)
+		}
+	}
+	for _, root := range cfg.Roots {
+		slog.Debug("loaded root", "label", root.Label, "path", root.Path)
 	}
-	fmt.Printf("Loaded root_directory: %s\n", cfg.RootDirectory)
 
 	return &cfg, nil
 }
+
+// resolveRoots fills in cfg.Roots. WORKSPACE_ROOT, if set, replaces every
+// configured root; otherwise root_directory is used when no [[roots]] are
+// configured. Each root inherits the [discovery] settings it leaves out, and
+// cfg.RootDirectory is set to the first root.
+func resolveRoots(cfg *Config) error {
+	if env := os.Getenv("WORKSPACE_ROOT"); env != "" {
+		cfg.Roots = []Root{{Path: env}}
+	} else if len(cfg.Roots) == 0 {
+		cfg.Roots = []Root{{Path: cfg.RootDirectory}}
+	}
+
+	labels := make(map[string]bool, len(cfg.Roots))
+	for i := range cfg.Roots {
+		root := &cfg.Roots[i]
+		if root.Path == "" {
+			return fmt.Errorf("roots entry without a path")
+		}
+		expanded, err := fileutil.ExpandHome(root.Path)
+		if err != nil {
+			return err
+		}
+		root.Path = filepath.Clean(expanded)
+		if _, err := os.Stat(root.Path); os.IsNotExist(err) {
+			return fmt.Errorf("root directory does not exist: %s", root.Path)
+		}
+
+		if root.Label == "" {
+			root.Label = filepath.Base(root.Path)
+		}
+		if strings.Contains(root.Label, ":") {
+			return fmt.Errorf("invalid root label %q (must not contain ':')", root.Label)
+		}
+		if labels[root.Label] {
+			return fmt.Errorf("duplicate root label %q; set a distinct label for each root", root.Label)
+		}
+		labels[root.Label] = true
+
+		if root.MaxDepth == 0 {
+			root.MaxDepth = cfg.Discovery.MaxDepth
+		}
+		if root.MaxDepth < 1 {
+			return fmt.Errorf("invalid max_depth %d for root %q (must be at least 1)", root.MaxDepth, root.Label)
+		}
+		if root.Nested == nil {
+			root.Nested = cfg.Discovery.Nested
+		}
+		if root.Exclude == nil {
+			root.Exclude = cfg.Discovery.Exclude
+		}
+		for _, pattern := range root.Exclude {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid exclude pattern %q for root %q: %w", pattern, root.Label, err)
+			}
+		}
+	}
+
+	cfg.RootDirectory = cfg.Roots[0].Path
+	return nil
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FileName is the name of the config file in each search directory.
+const FileName = "config.toml"
+
+// SearchPaths returns the config file locations LoadConfig tries when no
+// path is given, in order: $XDG_CONFIG_HOME/gotagmanager, ~/.config/gotagmanager,
+// and the current directory.
+func SearchPaths() []string {
+	var dirs []string
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		dirs = append(dirs, filepath.Join(configHome, "gotagmanager"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".config", "gotagmanager"))
+	}
+	if wd, err := os.Getwd(); err == nil {
+		dirs = append(dirs, wd)
+	} else {
+		dirs = append(dirs, ".")
+	}
+
+	var paths []string
+	seen := make(map[string]bool, len(dirs))
+	for _, dir := range dirs {
+		path := filepath.Join(dir, FileName)
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// DefaultPath is where a new config file is created: the first entry of
+// SearchPaths.
+func DefaultPath() string {
+	return SearchPaths()[0]
+}
+
+// FindFile returns the config file LoadConfig reads for configPath: the path
+// itself if it is set, otherwise the first of SearchPaths that exists. It
+// returns "" if there is none.
+func FindFile(configPath string) string {
+	if configPath != "" {
+		return configPath
+	}
+	for _, path := range SearchPaths() {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// Setting is one configuration value and where it came from.
+type Setting struct {
+	Key   string
+	Value interface{}
+	// Source is "default", "config file", "profile NAME", "command line",
+	// or "env NAME".
+	Source string
+}
+
+// Setting sources that do not name a profile or variable.
+const (
+	SourceDefault     = "default"
+	SourceFile        = "config file"
+	SourceCommandLine = "command line"
+)
+
+// collectSettings lists every key v knows, except the profile tables
+// themselves, with the source its value was taken from. profileSettings
+// holds the active profile's table, or nil.
+func collectSettings(v, profileSettings *viper.Viper, profile string) []Setting {
+	keys := v.AllKeys()
+	sort.Strings(keys)
+
+	var settings []Setting
+	for _, key := range keys {
+		if strings.HasPrefix(key, "profiles.") {
+			continue
+		}
+		settings = append(settings, Setting{
+			Key:    key,
+			Value:  v.Get(key),
+			Source: settingSource(v, profileSettings, profile, key),
+		})
+	}
+	return settings
+}
+
+// settingSource reports which layer the value of key comes from, from the
+// highest priority down.
+func settingSource(v, profileSettings *viper.Viper, profile, key string) string {
+	if os.Getenv("WORKSPACE_ROOT") != "" && (key == "root_directory" || key == "roots") {
+		return "env WORKSPACE_ROOT"
+	}
+	if !strings.Contains(key, ".") {
+		if _, ok := os.LookupEnv(strings.ToUpper(key)); ok {
+			return "env " + strings.ToUpper(key)
+		}
+	}
+	if profileSettings != nil && profileSettings.IsSet(key) {
+		return "profile " + profile
+	}
+	if v.InConfig(key) {
+		return SourceFile
+	}
+	return SourceDefault
+}
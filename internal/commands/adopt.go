@@ -0,0 +1,147 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/shell"
+	"github.com/johnjallday/GoTagManager/internal/tag"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// OrphansCommand lists directories under the root that have no ws_info.toml
+func OrphansCommand(cfg *config.Config, args []string) error {
+	orphans, err := workspace.ListAllOrphans(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to list directories: %w", err)
+	}
+
+	if len(orphans) == 0 {
+		fmt.Println("Every directory in the root has a ws_info.toml.")
+		return nil
+	}
+
+	fmt.Println("Directories without ws_info.toml:")
+	for _, orphan := range orphans {
+		fmt.Printf("- %s\n", orphan.Name)
+	}
+	return nil
+}
+
+// AdoptCommand creates a ws_info.toml in directories that lack one.
+// args names the directories to adopt; with no args every orphan is adopted.
+// Each gets inferred tags and an alias derived from its directory name.
+// When interactive is true the user confirms, edits, or skips each directory.
+func AdoptCommand(cfg *config.Config, args []string, interactive bool) error {
+	orphans, err := workspace.ListAllOrphans(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to list directories: %w", err)
+	}
+
+	if len(args) > 0 {
+		orphans, err = selectOrphans(cfg, orphans, args)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(orphans) == 0 {
+		fmt.Println("No directories to adopt.")
+		return nil
+	}
+
+	// Aliases already in use, including those handed out during this run
+	taken, err := existingAliases(cfg)
+	if err != nil {
+		return err
+	}
+
+	emitter := aliasEmitter(cfg)
+
+	adopted := 0
+	for _, orphan := range orphans {
+		dirName := orphan.Name
+
+		tags, err := inferTags(cfg, orphan.Path)
+		if err != nil {
+			return fmt.Errorf("failed to scan '%s': %w", dirName, err)
+		}
+
+		var aliases []string
+		alias := shell.SafeAliasName(path.Base(orphan.Rel))
+		if owner, exists := taken[alias]; exists {
+			slog.Warn("alias is already used; adopting without an alias", "alias", alias, "directory", dirName, "workspace", owner)
+		} else if alias != "" {
+			if err := shell.ValidateAliasName(alias, emitter, true); err != nil {
+				slog.Warn("adopting without an alias", "directory", dirName, "error", err)
+			} else {
+				aliases = []string{alias}
+			}
+		}
+
+		info := &workspace.WorkspaceInfo{
+			Accounts: map[string]string{},
+			Info:     workspace.InfoSection{Tags: tags, Aliases: workspace.PlainAliases(aliases)},
+		}
+
+		if interactive {
+			fmt.Printf("\n%s\n  tags:    %s\n  aliases: %s\n", dirName, strings.Join(tags, ", "), strings.Join(aliases, ", "))
+			answer := strings.ToLower(strings.TrimSpace(prompt.Input("Adopt? [y]es, [n]o, [e]dit, [q]uit: ", func(d prompt.Document) []prompt.Suggest {
+				return []prompt.Suggest{}
+			})))
+
+			switch answer {
+			case "", "y", "yes":
+			case "e", "edit":
+				info, err = wsInfoWizard(cfg, orphan.Path)
+				if err != nil {
+					return err
+				}
+				if info == nil {
+					continue
+				}
+			case "q", "quit":
+				fmt.Printf("Adopted %d of %d directories.\n", adopted, len(orphans))
+				return nil
+			default:
+				continue
+			}
+		}
+
+		if err := tag.CreateWsInfoToml(orphan.Path, *info); err != nil {
+			return fmt.Errorf("failed to adopt '%s': %w", dirName, err)
+		}
+		for _, a := range info.Info.Aliases {
+			taken[a.Name] = dirName
+		}
+		adopted++
+	}
+
+	fmt.Printf("Adopted %d of %d directories.\n", adopted, len(orphans))
+	return nil
+}
+
+// selectOrphans picks the named directories out of orphans. Names are paths
+// relative to the root, or unique final path elements.
+func selectOrphans(cfg *config.Config, orphans []workspace.Workspace, names []string) ([]workspace.Workspace, error) {
+	var selected []workspace.Workspace
+	for _, name := range names {
+		orphan, err := workspace.Find(orphans, name)
+		if err != nil {
+			if path, pathErr := rootPath(cfg, name); pathErr == nil {
+				if _, statErr := os.Stat(filepath.Join(path, "ws_info.toml")); statErr == nil {
+					return nil, fmt.Errorf("'%s' already has a ws_info.toml", name)
+				}
+			}
+			return nil, fmt.Errorf("'%s' is not an untagged directory in %s", name, rootList(cfg))
+		}
+		selected = append(selected, orphan)
+	}
+	return selected, nil
+}
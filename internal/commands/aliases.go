@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// AliasConflictsCommand lists every alias declared by more than one workspace,
+// with the ws_info.toml files that declare it.
+func AliasConflictsCommand(cfg *config.Config, args []string) error {
+	workspaces, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	collisions := workspace.FindAliasCollisions(workspace.CollectAliases(workspaces))
+	if len(collisions) == 0 {
+		fmt.Println("No alias conflicts found.")
+		return nil
+	}
+
+	for _, c := range collisions {
+		fmt.Printf("Alias '%s' is declared by %d workspaces:\n", c.Alias, len(c.Definitions))
+		for _, def := range c.Definitions {
+			fmt.Printf("  - %s (%s)\n", def.WorkspaceName, def.File)
+		}
+	}
+
+	fmt.Printf("\nCollision policy: %s\n", cfg.Aliases.CollisionPolicy)
+	return fmt.Errorf("%d alias conflict(s) found", len(collisions))
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/detect"
+	"github.com/johnjallday/GoTagManager/internal/tag"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// AutotagCommand infers tags from the contents of workspaces and reports them.
+// args are workspace names; with no args every workspace in the root is scanned.
+// When apply is true the inferred tags are added to each ws_info.toml.
+func AutotagCommand(cfg *config.Config, args []string, apply bool) error {
+	var workspaces []workspace.Workspace
+	var err error
+	if len(args) > 0 {
+		workspaces, err = resolveWorkspaceList(cfg, strings.Join(args, ","))
+	} else {
+		workspaces, err = workspace.ListAll(cfg.Roots)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(workspaces) == 0 {
+		fmt.Println("No valid workspaces found.")
+		return nil
+	}
+
+	inferred := make(map[string][]string, len(workspaces))
+	for _, ws := range workspaces {
+		tags, err := inferTags(cfg, ws.Path)
+		if err != nil {
+			return fmt.Errorf("failed to scan workspace '%s': %w", ws.Name, err)
+		}
+		inferred[ws.Path] = tags
+	}
+
+	updates, err := tag.PlanTagUpdates(workspaces, func(existing []string) []string {
+		return existing
+	})
+	if err != nil {
+		return fmt.Errorf("%w; no files were written", err)
+	}
+	for i := range updates {
+		updates[i].After = tag.AddTags(updates[i].Before, inferred[updates[i].Workspace.Path])
+	}
+
+	changed := 0
+	for _, update := range updates {
+		if update.Changed() {
+			printTagUpdate(update)
+			changed++
+		}
+	}
+
+	if !apply {
+		fmt.Printf("Dry run: %d workspace(s) would change; no files were written. Use --apply to write them.\n", changed)
+		return nil
+	}
+
+	if err := tag.ApplyTagUpdates(updates); err != nil {
+		return err
+	}
+	fmt.Printf("%d workspace(s) changed.\n", changed)
+	return nil
+}
+
+// inferTags runs the configured detectors against a workspace directory and
+// checks the result against the tag vocabulary. Inferred tags that the
+// vocabulary rejects are dropped with a warning rather than failing.
+func inferTags(cfg *config.Config, workspacePath string) ([]string, error) {
+	tags, err := detect.Detect(workspacePath, detect.Detectors(cfg.AutoTag))
+	if err != nil {
+		return nil, err
+	}
+
+	registry := tag.NewRegistry(cfg.Tags)
+	resolved, problems := registry.ResolveAll(tags)
+	for _, problem := range problems {
+		if registry.Strict() {
+			slog.Warn("skipping inferred tag", "workspace", filepath.Base(workspacePath), "error", problem)
+		} else {
+			slog.Warn(problem.Error())
+		}
+	}
+	return resolved, nil
+}
@@ -2,6 +2,8 @@ package commands
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
@@ -10,12 +12,15 @@ import (
 
 	"github.com/c-bata/go-prompt"
 	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/shell"
+	"github.com/johnjallday/GoTagManager/internal/tag"
 	"github.com/johnjallday/GoTagManager/internal/workspace"
 )
 
-// ListWorkspacesCommand lists all workspaces
-func ListWorkspacesCommand(cfg *config.Config, args []string) error {
-	workspaces, err := workspace.ListWorkspaces(cfg.RootDirectory)
+// ListWorkspacesCommand lists all workspaces, sorted by "name" or by
+// "frecency" (most used first, with when each was last visited).
+func ListWorkspacesCommand(cfg *config.Config, args []string, sortBy string) error {
+	workspaces, err := workspace.ListAll(cfg.Roots)
 	if err != nil {
 		return fmt.Errorf("failed to list workspaces: %w", err)
 	}
@@ -25,16 +30,24 @@ func ListWorkspacesCommand(cfg *config.Config, args []string) error {
 		return nil
 	}
 
+	switch sortBy {
+	case "", "name":
+	case "frecency":
+		return listByFrecency(workspaces)
+	default:
+		return fmt.Errorf("unknown sort order '%s' (expected name or frecency)", sortBy)
+	}
+
 	fmt.Println("Workspaces:")
 	for _, ws := range workspaces {
-		fmt.Printf("- %s\n", filepath.Base(ws))
+		fmt.Printf("- %s\n", ws.Name)
 	}
 	return nil
 }
 
 // ListAliasesCommand lists all aliases across workspaces
 func ListAliasesCommand(cfg *config.Config, args []string) error {
-	workspaces, err := workspace.ListWorkspaces(cfg.RootDirectory)
+	workspaces, err := workspace.ListAll(cfg.Roots)
 	if err != nil {
 		return fmt.Errorf("failed to list workspaces: %w", err)
 	}
@@ -44,10 +57,11 @@ func ListAliasesCommand(cfg *config.Config, args []string) error {
 		return nil
 	}
 
-	allAliases, err := workspace.ListAliases(workspaces, cfg.RootDirectory)
+	allAliases, collisions, err := workspace.ListAliases(workspaces, cfg.Aliases.CollisionPolicy)
 	if err != nil {
 		return fmt.Errorf("failed to list aliases: %w", err)
 	}
+	defer warnAliasCollisions(collisions)
 
 	if len(allAliases) == 0 {
 		fmt.Println("No aliases found in any workspace.")
@@ -70,68 +84,186 @@ func ListAliasesCommand(cfg *config.Config, args []string) error {
 	return nil
 }
 
-// GenerateAliasesCommand generates shell aliases
-func GenerateAliasesCommand(cfg *config.Config, args []string) error {
-	workspaces, err := workspace.ListWorkspaces(cfg.RootDirectory)
+// warnAliasCollisions points the user at "aliases conflicts" when aliases clash.
+func warnAliasCollisions(collisions []workspace.AliasCollision) {
+	if len(collisions) > 0 {
+		slog.Warn("aliases are declared by more than one workspace; run 'aliases conflicts' for details", "count", len(collisions))
+	}
+}
+
+// aliasAction resolves the paths of an alias entry against its workspace.
+func aliasAction(def workspace.AliasDefinition) shell.Action {
+	entry := def.Entry
+	action := shell.Action{
+		Dir:  def.WorkspacePath,
+		Env:  entry.Env,
+		Run:  entry.Run,
+		Tmux: entry.Tmux,
+	}
+	if entry.Dir != "" {
+		action.Dir = filepath.Join(def.WorkspacePath, entry.Dir)
+	}
+	if entry.Venv != "" {
+		action.Venv = entry.Venv
+		if !filepath.IsAbs(action.Venv) {
+			action.Venv = filepath.Join(action.Dir, action.Venv)
+		}
+	}
+	return action
+}
+
+// GenerateAliasesOptions controls the output of GenerateAliasesCommand.
+type GenerateAliasesOptions struct {
+	// Shell selects the emitter, e.g. "zsh" or "fish".
+	Shell string
+	// AllowPathShadowing permits alias names that match executables on $PATH.
+	AllowPathShadowing bool
+	// VisitCommand, if set, is run by every alias with the workspace path
+	// appended so that alias use is recorded in the visit history.
+	VisitCommand []string
+}
+
+// GenerateAliasesCommand generates shell aliases in the syntax of opts.Shell.
+// If args are given they form a tag expression, and only aliases of workspaces
+// whose effective tags match it are generated. Aliases with invalid names or
+// names that shadow builtins or executables are reported on stderr and skipped.
+func GenerateAliasesCommand(cfg *config.Config, args []string, opts GenerateAliasesOptions) error {
+	emitter, err := shell.Lookup(opts.Shell)
+	if err != nil {
+		return err
+	}
+	return writeAliases(cfg, args, opts, emitter, os.Stdout)
+}
+
+// writeAliases writes the generated alias script to w. Status messages are
+// written as comments so the output stays a valid script.
+func writeAliases(cfg *config.Config, args []string, opts GenerateAliasesOptions, emitter shell.Emitter, w io.Writer) error {
+	workspaces, err := workspace.ListAll(cfg.Roots)
 	if err != nil {
 		return fmt.Errorf("failed to list workspaces: %w", err)
 	}
 
+	if len(args) > 0 {
+		workspaces, err = filterWorkspacesByTags(cfg, workspaces, strings.Join(args, " "))
+		if err != nil {
+			return err
+		}
+	}
+
 	if len(workspaces) == 0 {
-		fmt.Println("No valid workspaces found.")
+		fmt.Fprintln(w, "# No valid workspaces found.")
 		return nil
 	}
 
-	allAliases, err := workspace.ListAliases(workspaces, cfg.RootDirectory)
+	definitions, collisions, err := workspace.ResolveAliasDefinitions(workspace.CollectAliases(workspaces), cfg.Aliases.CollisionPolicy)
 	if err != nil {
 		return fmt.Errorf("failed to list aliases: %w", err)
 	}
+	defer warnAliasCollisions(collisions)
 
-	if len(allAliases) == 0 {
-		fmt.Println("No aliases found in any workspace.")
+	if len(definitions) == 0 {
+		fmt.Fprintln(w, "# No aliases found in any workspace.")
 		return nil
 	}
 
-	// Map workspace names to their paths for quick lookup
-	workspacePathMap := make(map[string]string)
-	for _, workspacePath := range workspaces {
-		workspaceName := filepath.Base(workspacePath)
-		workspacePathMap[workspaceName] = workspacePath
-	}
-
-	fmt.Println("# Generated Aliases for GoTagManager")
+	fmt.Fprintln(w, "# Generated Aliases for GoTagManager")
 	// Sort aliases for consistent output.
-	aliasNames := make([]string, 0, len(allAliases))
-	for alias := range allAliases {
+	aliasNames := make([]string, 0, len(definitions))
+	for alias := range definitions {
 		aliasNames = append(aliasNames, alias)
 	}
 	sort.Strings(aliasNames)
 
+	rejected := 0
 	for _, alias := range aliasNames {
-		workspaceName := allAliases[alias]
-		workspacePath, exists := workspacePathMap[workspaceName]
-		if !exists {
-			fmt.Printf("# Warning: Workspace '%s' not found for alias '%s'\n", workspaceName, alias)
+		def := definitions[alias]
+		action := aliasAction(def)
+		if len(opts.VisitCommand) > 0 {
+			action.Hook = append(append([]string(nil), opts.VisitCommand...), def.WorkspacePath)
+		}
+		err := shell.ValidateAliasName(alias, emitter, !opts.AllowPathShadowing)
+		if err == nil {
+			err = shell.ValidateAction(action)
+		}
+		if err != nil {
+			slog.Warn("skipped alias", "workspace", def.WorkspaceName, "error", err)
+			rejected++
 			continue
 		}
-		fmt.Printf("alias %s=\"cd '%s'\"\n", alias, workspacePath)
+		fmt.Fprintln(w, emitter.Alias(alias, action))
+	}
+
+	if rejected > 0 {
+		slog.Warn("aliases skipped; fix them in ws_info.toml", "count", rejected)
 	}
 	return nil
 }
 
+// filterWorkspacesByTags keeps the workspaces whose effective tags match expr.
+func filterWorkspacesByTags(cfg *config.Config, workspaces []workspace.Workspace, expr string) ([]workspace.Workspace, error) {
+	query, err := tag.ParseQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []workspace.Workspace
+	for _, ws := range workspaces {
+		info, err := tag.Load(ws.Path, cfg.Tags.Implications)
+		if err != nil {
+			slog.Warn("failed to parse ws_info.toml", "path", filepath.Join(ws.Path, "ws_info.toml"), "error", err)
+			continue
+		}
+		if query.Match(info.EffectiveTags) {
+			matches = append(matches, ws)
+		}
+	}
+	return matches, nil
+}
+
+// impliedTags returns the effective tags of info that are not in its raw tags.
+func impliedTags(info *workspace.WorkspaceInfo) []string {
+	raw := make(map[string]bool, len(info.Info.Tags))
+	for _, t := range tag.NormalizeAll(info.Info.Tags) {
+		raw[t] = true
+	}
+
+	var implied []string
+	for _, t := range info.EffectiveTags {
+		if !raw[t] {
+			implied = append(implied, t)
+		}
+	}
+	return implied
+}
+
+// redactedValue replaces account values hidden by the accounts.redact rules.
+const redactedValue = "<redacted>"
+
+// accountValue returns the value of an account as it should be displayed.
+func accountValue(cfg *config.Config, key, value string) string {
+	if cfg.Accounts.Redacted(key) {
+		return redactedValue
+	}
+	return value
+}
+
 // InfoCommand displays information about a specific workspace
 func InfoCommand(cfg *config.Config, args []string) error {
 	if len(args) < 1 {
 		return fmt.Errorf("workspace name is required")
 	}
-	workspaceName := args[0]
-	wsPath := filepath.Join(cfg.RootDirectory, workspaceName)
+	ws, err := lookupWorkspace(cfg, args[0])
+	if err != nil {
+		return err
+	}
+	wsPath := ws.Path
 	wsInfoPath := filepath.Join(wsPath, "ws_info.toml")
 
-	info, err := workspace.ParseWSInfo(wsInfoPath)
+	info, err := tag.Load(wsPath, cfg.Tags.Implications)
 	if err != nil {
 		return fmt.Errorf("failed to parse %s: %w", wsInfoPath, err)
 	}
+	recordVisit(wsPath)
 
 	fmt.Printf("Contents of %s:\n", wsInfoPath)
 
@@ -139,15 +271,23 @@ func InfoCommand(cfg *config.Config, args []string) error {
 	if len(info.Accounts) > 0 {
 		fmt.Printf("Accounts:\n")
 		for key, value := range info.Accounts {
-			fmt.Printf("  %s = %s\n", key, value)
+			fmt.Printf("  %s = %s\n", key, accountValue(cfg, key, value))
 		}
 	}
 
 	// Print Tags
 	if len(info.Info.Tags) > 0 {
 		fmt.Printf("Tags:\n")
-		for _, tag := range info.Info.Tags {
-			fmt.Printf("  - %s\n", tag)
+		for _, t := range info.Info.Tags {
+			fmt.Printf("  - %s\n", t)
+		}
+	}
+
+	// Print Implied Tags
+	if implied := impliedTags(info); len(implied) > 0 {
+		fmt.Printf("Implied Tags:\n")
+		for _, t := range implied {
+			fmt.Printf("  - %s\n", t)
 		}
 	}
 
@@ -164,18 +304,18 @@ func InfoCommand(cfg *config.Config, args []string) error {
 // SelectWorkspaceInteractive lists all workspaces with numbers and prompts the user to select one.
 // It returns the selected workspace name.
 func SelectWorkspaceInteractive(cfg *config.Config) (string, error) {
-	workspaces, err := workspace.ListWorkspaces(cfg.RootDirectory)
+	workspaces, err := workspace.ListAll(cfg.Roots)
 	if err != nil {
 		return "", fmt.Errorf("failed to list workspaces: %w", err)
 	}
 
 	if len(workspaces) == 0 {
-		return "", fmt.Errorf("no workspaces found in root directory '%s'", cfg.RootDirectory)
+		return "", fmt.Errorf("no workspaces found in %s", rootList(cfg))
 	}
 
 	fmt.Println("Available Workspaces:")
 	for i, ws := range workspaces {
-		fmt.Printf("%d) %s\n", i+1, filepath.Base(ws))
+		fmt.Printf("%d) %s\n", i+1, ws.Name)
 	}
 
 	// Use go-prompt's Input to get user input
@@ -194,25 +334,27 @@ func SelectWorkspaceInteractive(cfg *config.Config) (string, error) {
 		return "", fmt.Errorf("invalid input")
 	}
 
-	selectedWorkspace := filepath.Base(workspaces[choice-1])
+	selectedWorkspace := workspaces[choice-1].Name
 	return selectedWorkspace, nil
 }
 
 // LoadWorkspaceCommand loads a workspace, displays its ws_info.toml, and lists all files and directories.
 func LoadWorkspaceCommand(cfg *config.Config, workspaceName string) error {
-	workspacePath := filepath.Join(cfg.RootDirectory, workspaceName)
-	wsInfoPath := filepath.Join(workspacePath, "ws_info.toml")
-
 	// Check if the workspace exists
-	if _, err := os.Stat(workspacePath); os.IsNotExist(err) {
-		return fmt.Errorf("workspace '%s' does not exist in root directory '%s'", workspaceName, cfg.RootDirectory)
+	ws, err := lookupWorkspace(cfg, workspaceName)
+	if err != nil {
+		return err
 	}
+	workspacePath := ws.Path
+	workspaceName = ws.Name
+	wsInfoPath := filepath.Join(workspacePath, "ws_info.toml")
 
 	// Parse ws_info.toml
-	info, err := workspace.ParseWSInfo(wsInfoPath)
+	info, err := tag.Load(workspacePath, cfg.Tags.Implications)
 	if err != nil {
 		return fmt.Errorf("failed to parse %s: %w", wsInfoPath, err)
 	}
+	recordVisit(workspacePath)
 
 	// Display ws_info.toml contents
 	fmt.Printf("\nContents of %s:\n", wsInfoPath)
@@ -221,7 +363,7 @@ func LoadWorkspaceCommand(cfg *config.Config, workspaceName string) error {
 	if len(info.Accounts) > 0 {
 		fmt.Printf("Accounts:\n")
 		for key, value := range info.Accounts {
-			fmt.Printf("  %s = %s\n", key, value)
+			fmt.Printf("  %s = %s\n", key, accountValue(cfg, key, value))
 		}
 	} else {
 		fmt.Println("No Accounts defined.")
@@ -230,13 +372,21 @@ func LoadWorkspaceCommand(cfg *config.Config, workspaceName string) error {
 	// Print Tags
 	if len(info.Info.Tags) > 0 {
 		fmt.Printf("Tags:\n")
-		for _, tag := range info.Info.Tags {
-			fmt.Printf("  - %s\n", tag)
+		for _, t := range info.Info.Tags {
+			fmt.Printf("  - %s\n", t)
 		}
 	} else {
 		fmt.Println("No Tags defined.")
 	}
 
+	// Print Implied Tags
+	if implied := impliedTags(info); len(implied) > 0 {
+		fmt.Printf("Implied Tags:\n")
+		for _, t := range implied {
+			fmt.Printf("  - %s\n", t)
+		}
+	}
+
 	// Print Aliases
 	if len(info.Info.Aliases) > 0 {
 		fmt.Printf("Aliases:\n")
@@ -277,7 +427,12 @@ func LoadWorkspaceCommand(cfg *config.Config, workspaceName string) error {
 
 // GetSizeCommand calculates and displays the size of a specified workspace
 func GetSizeCommand(cfg *config.Config, workspaceName string) error {
-	workspacePath := filepath.Join(cfg.RootDirectory, workspaceName)
+	ws, err := lookupWorkspace(cfg, workspaceName)
+	if err != nil {
+		return err
+	}
+	workspacePath := ws.Path
+	workspaceName = ws.Name
 	wsInfoPath := filepath.Join(workspacePath, "ws_info.toml")
 
 	// Check if the workspace exists
@@ -298,6 +453,52 @@ func GetSizeCommand(cfg *config.Config, workspaceName string) error {
 	return nil
 }
 
+// lookupWorkspace finds a workspace by name (see workspace.Find).
+func lookupWorkspace(cfg *config.Config, name string) (workspace.Workspace, error) {
+	workspaces, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return workspace.Workspace{}, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	ws, err := workspace.Find(workspaces, name)
+	if err != nil {
+		return workspace.Workspace{}, fmt.Errorf("%w in %s", err, rootList(cfg))
+	}
+	return ws, nil
+}
+
+// rootList describes the configured roots for messages.
+func rootList(cfg *config.Config) string {
+	paths := make([]string, len(cfg.Roots))
+	for i, root := range cfg.Roots {
+		paths[i] = "'" + root.Path + "'"
+	}
+	if len(paths) == 1 {
+		return "root directory " + paths[0]
+	}
+	return "root directories " + strings.Join(paths, ", ")
+}
+
+// rootPath turns a directory name such as "clients/acme" or "work:clients/acme"
+// into a path below the root with that label, or below the first root if the
+// name has no label.
+func rootPath(cfg *config.Config, name string) (string, error) {
+	root := cfg.Roots[0]
+	if label, rel, ok := strings.Cut(name, ":"); ok {
+		for _, r := range cfg.Roots {
+			if r.Label == label {
+				root, name = r, rel
+				break
+			}
+		}
+	}
+
+	path := filepath.Join(root.Path, filepath.FromSlash(name))
+	if rel, err := filepath.Rel(root.Path, path); err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("'%s' is not below root directory '%s'", name, root.Path)
+	}
+	return path, nil
+}
+
 // formatBytes converts bytes to a human-readable string
 func formatBytes(bytes int64) string {
 	const (
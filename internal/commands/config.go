@@ -0,0 +1,313 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/fileutil"
+	"github.com/johnjallday/GoTagManager/internal/shell"
+)
+
+// ConfigInitOptions holds the inputs of config init. Empty fields take the
+// defaults, which the interactive mode offers as suggestions.
+type ConfigInitOptions struct {
+	// Path is the file to write; empty selects config.DefaultPath.
+	Path string
+	// Force overwrites an existing file.
+	Force bool
+
+	RootDirectory string
+	MaxDepth      int
+	TagMode       string
+	Shell         string
+
+	// Interactive prompts for the settings above.
+	Interactive bool
+}
+
+// starterConfig is the commented config file written by config init.
+var starterConfig = template.Must(template.New("config").Parse(`# GoTagManager configuration, created by 'config init'.
+# 'config show' prints the effective settings and where each one comes from.
+
+# Directory whose subdirectories are workspaces. To search several
+# directories, replace it with one [[roots]] table per directory:
+#
+#   [[roots]]
+#   label = "work"
+#   path = "~/work"
+#   max_depth = 2
+root_directory = {{.RootDirectory}}
+
+[discovery]
+# Directory levels below the root searched for ws_info.toml; 1 = direct children only.
+max_depth = {{.MaxDepth}}
+# Also search inside workspaces for nested workspaces.
+nested = false
+# Directory names (or paths below the root) that are never searched.
+exclude = [".git", "node_modules", "__pycache__"]
+
+[tags]
+# "warn" reports tags outside the vocabulary; "strict" rejects them.
+# An empty vocabulary allows any tag.
+mode = {{.TagMode}}
+
+# [[tags.vocabulary]]
+# name = "lang/go"
+# description = "Go projects"
+# synonyms = ["golang", "go"]
+
+# [[tags.implications]]
+# if = "lang/*"
+# then = ["code"]
+
+[aliases]
+# How an alias declared by several workspaces is settled: "first-wins", "error", or "suffix".
+collision_policy = "first-wins"
+# Shell syntax for generate-aliases and aliases install.
+shell = {{.Shell}}
+
+[accounts]
+# Account keys whose values are hidden in command output.
+redact = ["*token*", "*password*", "*secret*"]
+
+# Profiles override any of the settings above. Select one with --profile or
+# $GTM_PROFILE, or set default_profile at the top of this file.
+#
+# [profiles.work]
+# root_directory = "~/work"
+# aliases.file = "~/.config/gotagmanager/work.zsh"
+# accounts.redact = ["*"]
+`))
+
+// ConfigInitCommand writes a commented starter config file.
+func ConfigInitCommand(opts ConfigInitOptions) error {
+	path := opts.Path
+	if path == "" {
+		path = config.DefaultPath()
+	}
+	path, err := fileutil.ExpandHome(path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil && !opts.Force {
+		return fmt.Errorf("%s already exists; use --force to overwrite it", path)
+	}
+
+	if opts.RootDirectory == "" {
+		opts.RootDirectory = config.DefaultRootDirectory
+	}
+	if opts.MaxDepth == 0 {
+		opts.MaxDepth = 1
+	}
+	if opts.TagMode == "" {
+		opts.TagMode = config.TagModeWarn
+	}
+	if opts.Shell == "" {
+		opts.Shell = shell.Detect("").Name()
+	}
+	if opts.Interactive {
+		if !configWizard(path, &opts) {
+			fmt.Println("Aborted; no file was written.")
+			return nil
+		}
+	}
+
+	if err := validateConfigInit(opts); err != nil {
+		return err
+	}
+	var content bytes.Buffer
+	if err := starterConfig.Execute(&content, map[string]interface{}{
+		"RootDirectory": strconv.Quote(opts.RootDirectory),
+		"MaxDepth":      opts.MaxDepth,
+		"TagMode":       strconv.Quote(opts.TagMode),
+		"Shell":         strconv.Quote(opts.Shell),
+	}); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := fileutil.WriteFileAtomic(path, 0644, func(f *os.File) error {
+		_, err := f.Write(content.Bytes())
+		return err
+	}); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %s\n", path)
+
+	if root, err := fileutil.ExpandHome(opts.RootDirectory); err == nil {
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			fmt.Printf("Note: the root directory %s does not exist yet.\n", root)
+		}
+	}
+	return nil
+}
+
+// configWizard prompts for the settings of a new config file. It returns
+// false if the user declines to write it.
+func configWizard(path string, opts *ConfigInitOptions) bool {
+	fmt.Printf("Creating %s. Press Enter to accept the value in brackets.\n", path)
+	noSuggestions := func(d prompt.Document) []prompt.Suggest { return nil }
+	choices := func(values ...string) prompt.Completer {
+		return func(d prompt.Document) []prompt.Suggest {
+			var s []prompt.Suggest
+			for _, v := range values {
+				s = append(s, prompt.Suggest{Text: v})
+			}
+			return prompt.FilterHasPrefix(s, d.GetWordBeforeCursor(), true)
+		}
+	}
+	ask := func(label, current string, completer prompt.Completer) string {
+		input := strings.TrimSpace(prompt.Input(fmt.Sprintf("%s [%s]: ", label, current), completer))
+		if input == "" {
+			return current
+		}
+		return input
+	}
+
+	opts.RootDirectory = ask("Workspace root directory", opts.RootDirectory, noSuggestions)
+	for {
+		input := ask("Directory levels to search below the root", strconv.Itoa(opts.MaxDepth), noSuggestions)
+		depth, err := strconv.Atoi(input)
+		if err == nil && depth >= 1 {
+			opts.MaxDepth = depth
+			break
+		}
+		fmt.Println("Error: enter a number of at least 1")
+	}
+	for {
+		opts.TagMode = ask("Tag vocabulary mode (warn or strict)", opts.TagMode, choices(config.TagModeWarn, config.TagModeStrict))
+		if opts.TagMode == config.TagModeWarn || opts.TagMode == config.TagModeStrict {
+			break
+		}
+		fmt.Printf("Error: expected %q or %q\n", config.TagModeWarn, config.TagModeStrict)
+	}
+	for {
+		opts.Shell = ask("Shell for generated aliases", opts.Shell, choices(shell.Names()...))
+		if _, err := shell.Lookup(opts.Shell); err == nil {
+			break
+		}
+		fmt.Printf("Error: expected one of %s\n", strings.Join(shell.Names(), ", "))
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(prompt.Input(fmt.Sprintf("Write %s? [Y/n]: ", path), noSuggestions)))
+	return answer == "" || answer == "y" || answer == "yes"
+}
+
+// validateConfigInit checks the settings given on the command line.
+func validateConfigInit(opts ConfigInitOptions) error {
+	if opts.MaxDepth < 1 {
+		return fmt.Errorf("invalid max depth %d (must be at least 1)", opts.MaxDepth)
+	}
+	if opts.TagMode != config.TagModeWarn && opts.TagMode != config.TagModeStrict {
+		return fmt.Errorf("invalid tag mode %q (expected %q or %q)", opts.TagMode, config.TagModeWarn, config.TagModeStrict)
+	}
+	if _, err := shell.Lookup(opts.Shell); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ConfigPathCommand prints the config file that is read for configPath. With
+// all, it prints every location searched and whether it exists.
+func ConfigPathCommand(configPath string, all bool) error {
+	file := config.FindFile(configPath)
+	if !all {
+		if file == "" {
+			return fmt.Errorf("no config file found (searched %s); run 'config init' to create one", strings.Join(config.SearchPaths(), ", "))
+		}
+		fmt.Println(file)
+		return nil
+	}
+
+	paths := config.SearchPaths()
+	if configPath != "" {
+		paths = []string{configPath}
+	}
+	for _, path := range paths {
+		status := "not found"
+		if path == file {
+			status = "in use"
+		} else if _, err := os.Stat(path); err == nil {
+			status = "exists"
+		}
+		fmt.Printf("%s (%s)\n", path, status)
+	}
+	return nil
+}
+
+// ConfigShowCommand prints every setting in effect with the place it came
+// from, followed by the resolved workspace roots.
+func ConfigShowCommand(cfg *config.Config) error {
+	file := cfg.File
+	if file == "" {
+		file = "(none)"
+	}
+	fmt.Printf("# Config file: %s\n", file)
+
+	lines := make([]string, len(cfg.Settings))
+	width := 0
+	for i, s := range cfg.Settings {
+		lines[i] = fmt.Sprintf("%s = %s", s.Key, formatSetting(s.Value))
+		if len(lines[i]) > width {
+			width = len(lines[i])
+		}
+	}
+	for i, s := range cfg.Settings {
+		fmt.Printf("%-*s  # %s\n", width, lines[i], s.Source)
+	}
+
+	fmt.Println("\n# Resolved roots:")
+	for _, root := range cfg.Roots {
+		fmt.Printf("#   %s: %s (max depth %d)\n", root.Label, root.Path, root.MaxDepth)
+	}
+	return nil
+}
+
+// formatSetting renders a setting value in TOML-like syntax.
+func formatSetting(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []string:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = strconv.Quote(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatSetting(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []map[string]interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatSetting(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			items[i] = k + " = " + formatSetting(v[k])
+		}
+		return "{ " + strings.Join(items, ", ") + " }"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/tag"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// defaultCurrentFormat is the output of CurrentCommand without --format.
+const defaultCurrentFormat = `Name: {{.Name}}
+Path: {{.Path}}
+Tags: {{join .Tags ", "}}
+Aliases: {{join .Aliases ", "}}
+`
+
+// CurrentWorkspace is the data available to the --format template of
+// CurrentCommand.
+type CurrentWorkspace struct {
+	Name          string
+	Path          string
+	Tags          []string // tags as written in ws_info.toml
+	EffectiveTags []string // Tags plus implied tags
+	Aliases       []string
+}
+
+// CurrentCommand prints the workspace containing args[0], or the working
+// directory if no path is given. format is a Go template over
+// CurrentWorkspace, e.g. "{{.Name}} [{{join .Tags \",\"}}]"; if it is empty a
+// multi-line summary is printed. It returns workspace.ErrNotInWorkspace if
+// the path is not inside a workspace.
+func CurrentCommand(cfg *config.Config, args []string, format string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	tmpl, err := workspaceTemplate(format, defaultCurrentFormat)
+	if err != nil {
+		return err
+	}
+
+	ws, err := workspace.FindEnclosingAll(cfg.Roots, path)
+	if err != nil {
+		return err
+	}
+	info, err := tag.Load(ws.Path, cfg.Tags.Implications)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Join(ws.Path, "ws_info.toml"), err)
+	}
+
+	current := CurrentWorkspace{
+		Name:          ws.Name,
+		Path:          ws.Path,
+		Tags:          info.Info.Tags,
+		EffectiveTags: info.EffectiveTags,
+		Aliases:       workspace.AliasNames(info.Info.Aliases),
+	}
+	return tmpl.Execute(os.Stdout, current)
+}
+
+// workspaceTemplate parses a --format template over CurrentWorkspace, using
+// fallback if format is empty.
+func workspaceTemplate(format, fallback string) (*template.Template, error) {
+	if format == "" {
+		format = fallback
+	} else {
+		// Allow escaped newlines and tabs from the command line
+		format = strings.NewReplacer(`\n`, "\n", `\t`, "\t").Replace(format)
+	}
+
+	tmpl, err := template.New("workspace").Funcs(template.FuncMap{"join": strings.Join}).Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format: %w", err)
+	}
+	return tmpl, nil
+}
@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/tag"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// FindCommand lists all workspaces whose tags satisfy a boolean tag expression
+func FindCommand(cfg *config.Config, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("tag expression is required")
+	}
+	expr := strings.Join(args, " ")
+
+	query, err := tag.ParseQuery(expr)
+	if err != nil {
+		return err
+	}
+
+	workspaces, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	var matches []string
+	for _, ws := range workspaces {
+		workspacePath := ws.Path
+		info, err := tag.Load(workspacePath, cfg.Tags.Implications)
+		if err != nil {
+			slog.Warn("failed to parse ws_info.toml", "path", filepath.Join(workspacePath, "ws_info.toml"), "error", err)
+			continue
+		}
+
+		if query.Match(info.EffectiveTags) {
+			matches = append(matches, ws.Name)
+		}
+	}
+
+	if len(matches) == 0 {
+		fmt.Printf("No workspaces match '%s'.\n", expr)
+		return nil
+	}
+
+	fmt.Printf("Workspaces matching '%s':\n", expr)
+	for _, name := range matches {
+		fmt.Printf("- %s\n", name)
+	}
+	return nil
+}
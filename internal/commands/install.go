@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/fileutil"
+	"github.com/johnjallday/GoTagManager/internal/shell"
+)
+
+// InstallAliasesOptions controls where InstallAliasesCommand writes aliases.
+type InstallAliasesOptions struct {
+	GenerateAliasesOptions
+
+	// RCFile is the shell startup file holding the managed block. Empty
+	// selects the default rc file of the shell.
+	RCFile string
+	// File, when set, receives the generated aliases as a standalone script,
+	// and the rc file only gets a line that sources it.
+	File string
+	// Standalone selects the standalone mode with the default File,
+	// ~/.config/gotagmanager/aliases plus the shell's script extension.
+	Standalone bool
+}
+
+// InstallAliasesCommand writes the generated aliases between marker comments
+// in a shell rc file. Re-running it replaces the block without touching the
+// rest of the file. The previous rc file is kept as a .gtm.bak backup.
+func InstallAliasesCommand(cfg *config.Config, args []string, opts InstallAliasesOptions) error {
+	emitter, rcFile, err := resolveInstallTarget(&opts)
+	if err != nil {
+		return err
+	}
+
+	var script bytes.Buffer
+	if err := writeAliases(cfg, args, opts.GenerateAliasesOptions, emitter, &script); err != nil {
+		return err
+	}
+
+	body := script.String()
+	if opts.File != "" {
+		// The rc file sources the path as given; the script is written to
+		// the file it links to
+		target, err := resolveLink(opts.File)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := writeIfChanged(target, body); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote aliases to %s\n", target)
+		body = emitter.Source(opts.File)
+	}
+
+	content, perm, err := readRCFile(rcFile)
+	if err != nil {
+		return err
+	}
+
+	updated := shell.ReplaceBlock(content, body)
+	if updated == content {
+		fmt.Printf("%s is already up to date.\n", rcFile)
+		return nil
+	}
+
+	if err := backupRCFile(rcFile, content); err != nil {
+		return err
+	}
+	if err := fileutil.WriteFileAtomic(rcFile, perm, func(f *os.File) error {
+		_, err := f.WriteString(updated)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Installed %s aliases into %s\n", emitter.Name(), rcFile)
+	return nil
+}
+
+// UninstallAliasesCommand removes the managed block from a shell rc file and,
+// if opts.File is set, deletes the standalone alias script.
+func UninstallAliasesCommand(opts InstallAliasesOptions) error {
+	_, rcFile, err := resolveInstallTarget(&opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.File != "" {
+		file, err := resolveLink(opts.File)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(file); err == nil {
+			fmt.Printf("Removed %s\n", file)
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	content, perm, err := readRCFile(rcFile)
+	if err != nil {
+		return err
+	}
+
+	updated, found := shell.RemoveBlock(content)
+	if !found {
+		fmt.Printf("No GoTagManager block found in %s.\n", rcFile)
+		return nil
+	}
+
+	if err := backupRCFile(rcFile, content); err != nil {
+		return err
+	}
+	if err := fileutil.WriteFileAtomic(rcFile, perm, func(f *os.File) error {
+		_, err := f.WriteString(updated)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed GoTagManager aliases from %s\n", rcFile)
+	return nil
+}
+
+// resolveInstallTarget picks the emitter and the absolute rc file path, and
+// fills in the default standalone file with ~ expanded. The shell is taken
+// from opts.Shell, or guessed from the rc file name. A symlinked rc file is
+// resolved to the file it points to, so dotfile managers keep their links.
+func resolveInstallTarget(opts *InstallAliasesOptions) (shell.Emitter, string, error) {
+	var emitter shell.Emitter
+	if opts.Shell != "" {
+		e, err := shell.Lookup(opts.Shell)
+		if err != nil {
+			return nil, "", err
+		}
+		emitter = e
+	} else {
+		emitter = shell.Detect(opts.RCFile)
+	}
+
+	if opts.Standalone && opts.File == "" {
+		opts.File = filepath.Join("~", ".config", "gotagmanager", "aliases"+emitter.Extension())
+	}
+
+	rcFile := opts.RCFile
+	if rcFile == "" {
+		rcFile = filepath.Join("~", emitter.RCFile())
+	}
+	if opts.File != "" {
+		file, err := fileutil.ExpandHome(opts.File)
+		if err != nil {
+			return nil, "", err
+		}
+		opts.File = file
+	}
+
+	rcFile, err := resolveLink(rcFile)
+	if err != nil {
+		return nil, "", err
+	}
+	return emitter, rcFile, nil
+}
+
+// resolveLink expands ~ in path and follows symlinks, so that files are
+// written where a link such as ~/.zshrc -> ~/dotfiles/zshrc points instead
+// of replacing the link. A link to a file that does not exist yet resolves
+// to that file; a missing path is returned as is.
+func resolveLink(path string) (string, error) {
+	path, err := fileutil.ExpandHome(path)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err == nil {
+		return resolved, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+
+	for i := 0; i < 255; i++ {
+		target, err := os.Readlink(path)
+		if err != nil {
+			return path, nil
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), target)
+		}
+		path = target
+	}
+	return "", fmt.Errorf("too many levels of symbolic links: %s", path)
+}
+
+// readRCFile returns the contents and permissions of an rc file. A missing
+// file reads as empty.
+func readRCFile(rcFile string) (string, os.FileMode, error) {
+	data, err := os.ReadFile(rcFile)
+	if os.IsNotExist(err) {
+		return "", 0644, os.MkdirAll(filepath.Dir(rcFile), 0755)
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	stat, err := os.Stat(rcFile)
+	if err != nil {
+		return "", 0, err
+	}
+	return string(data), stat.Mode().Perm(), nil
+}
+
+// backupRCFile saves the current contents of an rc file next to it.
+func backupRCFile(rcFile, content string) error {
+	if content == "" {
+		return nil
+	}
+	backup := rcFile + ".gtm.bak"
+	if err := os.WriteFile(backup, []byte(content), 0600); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", rcFile, err)
+	}
+	fmt.Printf("Backed up %s to %s\n", rcFile, backup)
+	return nil
+}
+
+// writeIfChanged atomically writes content to file unless it already holds it.
+func writeIfChanged(file, content string) error {
+	if existing, err := os.ReadFile(file); err == nil && string(existing) == content {
+		return nil
+	}
+	return fileutil.WriteFileAtomic(file, 0644, func(f *os.File) error {
+		_, err := f.WriteString(content)
+		return err
+	})
+}
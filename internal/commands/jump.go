@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/fuzzy"
+	"github.com/johnjallday/GoTagManager/internal/history"
+	"github.com/johnjallday/GoTagManager/internal/shell"
+	"github.com/johnjallday/GoTagManager/internal/tag"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// JumpOptions controls JumpCommand.
+type JumpOptions struct {
+	// List prints every matching workspace with its score instead of
+	// printing the best path and recording a visit.
+	List bool
+}
+
+// jumpMatch is a workspace that matched a jump query.
+type jumpMatch struct {
+	Name     string
+	Path     string
+	Score    int
+	Frecency float64
+}
+
+// JumpCommand fuzzy-matches the query terms against workspace names, aliases
+// and tags and prints the path of the best match. Equal scores are broken by
+// frecency, and every successful jump is recorded in the visit history.
+func JumpCommand(cfg *config.Config, args []string, opts JumpOptions) error {
+	if len(args) < 1 {
+		return fmt.Errorf("query is required")
+	}
+
+	hist, err := history.Open()
+	recordVisit := err == nil
+	if err != nil {
+		// History only breaks ties, so jumping works without it. The visit
+		// is not recorded either, which would overwrite the unreadable file.
+		slog.Warn("failed to load history", "error", err)
+		hist = history.New("")
+	}
+
+	matches, err := rankWorkspaces(cfg, args, hist)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no workspace matches '%s'", strings.Join(args, " "))
+	}
+
+	if opts.List {
+		for _, m := range matches {
+			fmt.Printf("%6d %8.1f  %s\t%s\n", m.Score, m.Frecency, m.Name, m.Path)
+		}
+		return nil
+	}
+
+	best := matches[0]
+	if recordVisit {
+		hist.Visit(best.Path, time.Now())
+		if err := hist.Save(); err != nil {
+			// The jump itself still works without history.
+			slog.Warn("failed to save history", "error", err)
+		}
+	}
+	fmt.Println(best.Path)
+	return nil
+}
+
+// JumpInitCommand prints the shell function that cds into the result of
+// command, e.g. so that "j foo" jumps to the best match for "foo".
+func JumpInitCommand(shellName, name string, command []string) error {
+	emitter, err := shell.Lookup(shellName)
+	if err != nil {
+		return err
+	}
+	if err := shell.ValidateAliasSyntax(name); err != nil {
+		return err
+	}
+	fmt.Println(emitter.Jump(name, command))
+	return nil
+}
+
+// rankWorkspaces returns the workspaces matching every query term, best
+// first. A term matches a workspace through its name, any of its aliases, or
+// any of its effective tags; tags count for half since many workspaces share
+// them. The scores of all terms are added up.
+func rankWorkspaces(cfg *config.Config, terms []string, hist *history.History) ([]jumpMatch, error) {
+	workspaces, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	now := time.Now()
+	var matches []jumpMatch
+	for _, ws := range workspaces {
+		workspacePath := ws.Path
+		info, err := tag.Load(workspacePath, cfg.Tags.Implications)
+		if err != nil {
+			slog.Warn("failed to parse ws_info.toml", "path", filepath.Join(workspacePath, "ws_info.toml"), "error", err)
+			continue
+		}
+
+		name := ws.Name
+		total, matched := 0, true
+		for _, term := range terms {
+			best, ok := fuzzy.Score(term, name)
+			for _, alias := range info.Info.Aliases {
+				if s, aliasOK := fuzzy.Score(term, alias.Name); aliasOK && (!ok || s > best) {
+					best, ok = s, true
+				}
+			}
+			for _, t := range info.EffectiveTags {
+				if s, tagOK := fuzzy.Score(term, t); tagOK && (!ok || s/2 > best) {
+					best, ok = s/2, true
+				}
+			}
+			if !ok {
+				matched = false
+				break
+			}
+			total += best
+		}
+		if !matched {
+			continue
+		}
+
+		matches = append(matches, jumpMatch{
+			Name:     name,
+			Path:     workspacePath,
+			Score:    total,
+			Frecency: hist.Frecency(workspacePath, now),
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		if matches[i].Frecency != matches[j].Frecency {
+			return matches[i].Frecency > matches[j].Frecency
+		}
+		return matches[i].Name < matches[j].Name
+	})
+	return matches, nil
+}
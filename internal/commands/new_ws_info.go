@@ -0,0 +1,270 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/shell"
+	"github.com/johnjallday/GoTagManager/internal/tag"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// NewWsInfoOptions holds the non-interactive inputs of new_ws_info.
+type NewWsInfoOptions struct {
+	Tags     []string
+	Aliases  []string
+	Accounts []string // "key=value" pairs
+
+	// Interactive prompts for tags, aliases, and accounts instead of using
+	// the fields above.
+	Interactive bool
+}
+
+// NewWsInfoCommand creates a ws_info.toml in a workspace.
+// args[0] is the workspace name; any remaining args are additional tags.
+// Without explicit tags, tags are inferred from the workspace contents.
+func NewWsInfoCommand(cfg *config.Config, args []string, opts NewWsInfoOptions) error {
+	if len(args) < 1 {
+		return fmt.Errorf("workspace name is required")
+	}
+	workspaceName := args[0]
+
+	// Build the path to the workspace from the config root; nested
+	// workspaces are named by their path relative to it
+	workspacePath, err := rootPath(cfg, workspaceName)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(workspacePath); os.IsNotExist(err) {
+		return fmt.Errorf("workspace path does not exist: %s", workspacePath)
+	}
+	if _, err := os.Stat(filepath.Join(workspacePath, "ws_info.toml")); err == nil {
+		return fmt.Errorf("ws_info.toml already exists in %s", workspacePath)
+	}
+
+	var info *workspace.WorkspaceInfo
+	if opts.Interactive {
+		info, err = wsInfoWizard(cfg, workspacePath)
+	} else {
+		opts.Tags = append(opts.Tags, args[1:]...)
+		info, err = wsInfoFromOptions(cfg, workspacePath, opts)
+	}
+	if err != nil {
+		return err
+	}
+	if info == nil {
+		fmt.Println("Aborted; no file was written.")
+		return nil
+	}
+
+	if err := tag.CreateWsInfoToml(workspacePath, *info); err != nil {
+		return fmt.Errorf("error creating ws_info.toml: %w", err)
+	}
+	return nil
+}
+
+// wsInfoFromOptions builds a WorkspaceInfo from command-line flags.
+func wsInfoFromOptions(cfg *config.Config, workspacePath string, opts NewWsInfoOptions) (*workspace.WorkspaceInfo, error) {
+	var tags []string
+	var err error
+	if len(opts.Tags) > 0 {
+		tags, err = resolveTags(cfg, opts.Tags)
+	} else {
+		tags, err = inferTags(cfg, workspacePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	existingAliases, err := existingAliases(cfg)
+	if err != nil {
+		return nil, err
+	}
+	aliases := dedupe(opts.Aliases)
+	emitter := aliasEmitter(cfg)
+	for _, alias := range aliases {
+		if err := shell.ValidateAliasName(alias, emitter, true); err != nil {
+			return nil, err
+		}
+		if owner, taken := existingAliases[alias]; taken {
+			return nil, fmt.Errorf("alias '%s' is already used by workspace '%s'", alias, owner)
+		}
+	}
+
+	accounts, err := parseAccounts(opts.Accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workspace.WorkspaceInfo{
+		Accounts: accounts,
+		Info:     workspace.InfoSection{Tags: tags, Aliases: workspace.PlainAliases(aliases)},
+	}, nil
+}
+
+// wsInfoWizard prompts for tags, aliases, and accounts. It returns nil if the
+// user declines to write the result.
+func wsInfoWizard(cfg *config.Config, workspacePath string) (*workspace.WorkspaceInfo, error) {
+	workspaceName := filepath.Base(workspacePath)
+	fmt.Printf("Creating ws_info.toml for workspace '%s'.\n", workspaceName)
+
+	// Tags: complete from the tags already used across the root
+	inferred, err := inferTags(cfg, workspacePath)
+	if err != nil {
+		return nil, err
+	}
+	knownTags, err := AllTags(cfg)
+	if err != nil {
+		return nil, err
+	}
+	tagCompleter := func(d prompt.Document) []prompt.Suggest {
+		var s []prompt.Suggest
+		for _, t := range tag.CompleteLevel(knownTags, strings.ToLower(d.GetWordBeforeCursor())) {
+			s = append(s, prompt.Suggest{Text: t, Description: "Tag"})
+		}
+		return s
+	}
+
+	var tags []string
+	for {
+		if len(inferred) > 0 {
+			fmt.Printf("Inferred tags: %s (press Enter to accept)\n", strings.Join(inferred, " "))
+		}
+		input := strings.Fields(prompt.Input("Tags (space-separated): ", tagCompleter))
+		if len(input) == 0 {
+			input = inferred
+		}
+		tags, err = resolveTags(cfg, input)
+		if err == nil {
+			break
+		}
+		fmt.Printf("Error: %v\n", err)
+	}
+
+	// Aliases: flag collisions with existing aliases while typing
+	existing, err := existingAliases(cfg)
+	if err != nil {
+		return nil, err
+	}
+	aliasCompleter := func(d prompt.Document) []prompt.Suggest {
+		word := d.GetWordBeforeCursor()
+		if owner, taken := existing[word]; taken {
+			return []prompt.Suggest{{Text: word, Description: fmt.Sprintf("taken by '%s'", owner)}}
+		}
+		return nil
+	}
+
+	emitter := aliasEmitter(cfg)
+	var aliases []string
+	for {
+		aliases = dedupe(strings.Fields(prompt.Input("Aliases (space-separated): ", aliasCompleter)))
+		var problems []string
+		for _, alias := range aliases {
+			if err := shell.ValidateAliasName(alias, emitter, true); err != nil {
+				problems = append(problems, err.Error())
+				continue
+			}
+			if owner, taken := existing[alias]; taken {
+				problems = append(problems, fmt.Sprintf("'%s' is already used by workspace '%s'", alias, owner))
+			}
+		}
+		if len(problems) == 0 {
+			break
+		}
+		fmt.Printf("Error: %s\n", strings.Join(problems, "; "))
+	}
+
+	// Accounts: one key=value pair per line until a blank line
+	accounts := make(map[string]string)
+	noSuggestions := func(d prompt.Document) []prompt.Suggest { return nil }
+	for {
+		input := strings.TrimSpace(prompt.Input("Account (key=value, blank to finish): ", noSuggestions))
+		if input == "" {
+			break
+		}
+		parsed, err := parseAccounts([]string{input})
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			continue
+		}
+		for k, v := range parsed {
+			accounts[k] = v
+		}
+	}
+
+	info := &workspace.WorkspaceInfo{
+		Accounts: accounts,
+		Info:     workspace.InfoSection{Tags: tags, Aliases: workspace.PlainAliases(aliases)},
+	}
+
+	fmt.Printf("\nTags:     %s\n", strings.Join(tags, ", "))
+	fmt.Printf("Aliases:  %s\n", strings.Join(aliases, ", "))
+	accountKeys := make([]string, 0, len(accounts))
+	for k := range accounts {
+		accountKeys = append(accountKeys, k)
+	}
+	sort.Strings(accountKeys)
+	fmt.Printf("Accounts: %s\n", strings.Join(accountKeys, ", "))
+
+	answer := strings.ToLower(strings.TrimSpace(prompt.Input("Write ws_info.toml? [Y/n]: ", noSuggestions)))
+	if answer != "" && answer != "y" && answer != "yes" {
+		return nil, nil
+	}
+	return info, nil
+}
+
+// aliasEmitter returns the emitter of the shell aliases are generated for:
+// aliases.shell from the config, or else the user's shell.
+func aliasEmitter(cfg *config.Config) shell.Emitter {
+	if e, err := shell.Lookup(cfg.Aliases.Shell); err == nil {
+		return e
+	}
+	return shell.Detect("")
+}
+
+// existingAliases returns every alias already declared across the root,
+// mapped to the first workspace declaring it.
+func existingAliases(cfg *config.Config) (map[string]string, error) {
+	workspaces, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	aliases, _, err := workspace.ResolveAliases(workspace.CollectAliases(workspaces), workspace.CollisionFirstWins)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+	return aliases, nil
+}
+
+// parseAccounts parses "key=value" pairs.
+func parseAccounts(pairs []string) (map[string]string, error) {
+	accounts := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid account '%s'; expected key=value", pair)
+		}
+		accounts[key] = strings.TrimSpace(value)
+	}
+	return accounts, nil
+}
+
+// dedupe drops empty strings and duplicates while preserving order.
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
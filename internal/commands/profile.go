@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/johnjallday/GoTagManager/config"
+)
+
+// ProfileListCommand prints the profiles defined in the config file, marking
+// the active one with '*'.
+func ProfileListCommand(cfg *config.Config) error {
+	if len(cfg.Profiles) == 0 {
+		fmt.Println("No profiles defined.")
+		return nil
+	}
+	for _, name := range cfg.Profiles {
+		marker := " "
+		if name == cfg.Profile {
+			marker = "*"
+		}
+		fmt.Printf("%s %s\n", marker, name)
+	}
+	return nil
+}
+
+// ProfileShowCommand prints the settings in effect for the profile cfg was
+// loaded with.
+func ProfileShowCommand(cfg *config.Config) error {
+	name := cfg.Profile
+	if name == "" {
+		name = "(none)"
+	}
+	fmt.Printf("Profile: %s\n", name)
+
+	fmt.Println("Roots:")
+	for _, root := range cfg.Roots {
+		fmt.Printf("  %s: %s (max depth %d", root.Label, root.Path, root.MaxDepth)
+		if root.IsNested() {
+			fmt.Print(", nested")
+		}
+		fmt.Println(")")
+	}
+
+	fmt.Println("Tags:")
+	fmt.Printf("  mode: %s\n", cfg.Tags.Mode)
+	if len(cfg.Tags.Vocabulary) > 0 {
+		names := make([]string, len(cfg.Tags.Vocabulary))
+		for i, def := range cfg.Tags.Vocabulary {
+			names[i] = def.Name
+		}
+		fmt.Printf("  vocabulary: %s\n", strings.Join(names, ", "))
+	} else {
+		fmt.Println("  vocabulary: any tag")
+	}
+	fmt.Printf("  implications: %d\n", len(cfg.Tags.Implications))
+
+	fmt.Println("Aliases:")
+	fmt.Printf("  collision policy: %s\n", cfg.Aliases.CollisionPolicy)
+	fmt.Printf("  shell: %s\n", valueOrDefault(cfg.Aliases.Shell))
+	fmt.Printf("  rc file: %s\n", valueOrDefault(cfg.Aliases.RCFile))
+	fmt.Printf("  file: %s\n", valueOrDefault(cfg.Aliases.File))
+
+	fmt.Println("Accounts:")
+	if len(cfg.Accounts.Redact) > 0 {
+		fmt.Printf("  redact: %s\n", strings.Join(cfg.Accounts.Redact, ", "))
+	} else {
+		fmt.Println("  redact: nothing")
+	}
+	return nil
+}
+
+// valueOrDefault returns s, or "(default)" if it is empty.
+func valueOrDefault(s string) string {
+	if s == "" {
+		return "(default)"
+	}
+	return s
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/index"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// defaultPromptFormat is the output of PromptSegmentCommand without --format.
+const defaultPromptFormat = `{{.Name}}{{if .Tags}} [{{join .Tags ","}}]{{end}}`
+
+// PromptSegmentCommand prints the prompt segment for the workspace containing
+// args[0], or the working directory, and nothing at all outside a workspace.
+// The workspace data comes from the index, so on a warm cache only the
+// directories between the path and the root are stat'ed and no TOML is
+// parsed.
+func PromptSegmentCommand(cfg *config.Config, args []string, format string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+	tmpl, err := workspaceTemplate(format, defaultPromptFormat)
+	if err != nil {
+		return err
+	}
+
+	ws, err := workspace.FindEnclosingAll(cfg.Roots, path)
+	if errors.Is(err, workspace.ErrNotInWorkspace) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ix, err := index.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load index: %w", err)
+	}
+	entry, err := ix.Lookup(ws)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(os.Stdout, CurrentWorkspace{
+		Name:          entry.Name,
+		Path:          entry.Path,
+		Tags:          entry.Tags,
+		EffectiveTags: entry.EffectiveTags,
+		Aliases:       entry.Aliases,
+	})
+}
+
+// IndexCommand rebuilds the workspace index used by prompt-segment.
+func IndexCommand(cfg *config.Config) error {
+	ix, err := index.Rebuild(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild index: %w", err)
+	}
+	fmt.Printf("Indexed %d workspaces.\n", len(ix.Workspaces))
+	return nil
+}
@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/history"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// RecentCommand lists the most recently visited workspaces, newest first.
+func RecentCommand(cfg *config.Config, limit int) error {
+	return listVisited(cfg, limit, func(a, b history.Entry, now time.Time) bool {
+		return a.LastVisit.After(b.LastVisit)
+	})
+}
+
+// FrequentCommand lists the workspaces with the highest frecency first.
+func FrequentCommand(cfg *config.Config, limit int) error {
+	return listVisited(cfg, limit, func(a, b history.Entry, now time.Time) bool {
+		return a.Frecency(now) > b.Frecency(now)
+	})
+}
+
+// VisitCommand records a visit to the workspace at args[0]. Generated alias
+// hooks call it each time an alias is used.
+func VisitCommand(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("workspace path is required")
+	}
+	path, err := filepath.Abs(args[0])
+	if err != nil {
+		return err
+	}
+	return history.Record(path)
+}
+
+// recordVisit adds a visit to the history, warning on stderr if that fails
+// since the command that resolved the workspace has still succeeded.
+func recordVisit(workspacePath string) {
+	if err := history.Record(workspacePath); err != nil {
+		slog.Warn("failed to record visit", "error", err)
+	}
+}
+
+// listByFrecency prints every workspace, most used first. Workspaces that
+// were never visited come last, in name order.
+func listByFrecency(workspaces []workspace.Workspace) error {
+	hist, err := history.Open()
+	if err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+
+	now := time.Now()
+	sorted := append([]workspace.Workspace(nil), workspaces...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return hist.Frecency(sorted[i].Path, now) > hist.Frecency(sorted[j].Path, now)
+	})
+
+	fmt.Println("Workspaces:")
+	for _, ws := range sorted {
+		if e, ok := hist.Entry(ws.Path); ok {
+			fmt.Printf("- %-30s %8.1f  %s\n", ws.Name, e.Frecency(now), formatAge(now.Sub(e.LastVisit)))
+		} else {
+			fmt.Printf("- %-30s %8s  never\n", ws.Name, "-")
+		}
+	}
+	return nil
+}
+
+// listVisited prints up to limit visited workspaces that still exist, in the
+// order given by less. A limit of 0 or less prints all of them.
+func listVisited(cfg *config.Config, limit int, less func(a, b history.Entry, now time.Time) bool) error {
+	workspaces, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	hist, err := history.Open()
+	if err != nil {
+		return fmt.Errorf("failed to load history: %w", err)
+	}
+
+	var entries []history.Entry
+	names := make(map[string]string)
+	for _, ws := range workspaces {
+		if e, ok := hist.Entry(ws.Path); ok {
+			entries = append(entries, e)
+			names[e.Path] = ws.Name
+		}
+	}
+	if len(entries) == 0 {
+		fmt.Println("No visited workspaces yet.")
+		return nil
+	}
+
+	now := time.Now()
+	sort.SliceStable(entries, func(i, j int) bool { return less(entries[i], entries[j], now) })
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	for _, e := range entries {
+		fmt.Printf("%-30s %8.1f  %4d visits  %s\n", names[e.Path], e.Frecency(now), e.Visits, formatAge(now.Sub(e.LastVisit)))
+	}
+	return nil
+}
+
+// formatAge renders a duration as a short "... ago" string.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
@@ -0,0 +1,317 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/tag"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// TagAddCommand adds tags to one or more workspaces.
+// args[0] is a comma-separated list of workspaces; the remaining args are tags.
+func TagAddCommand(cfg *config.Config, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: tag add WORKSPACE[,WORKSPACE...] TAG...")
+	}
+	tags, err := resolveTags(cfg, args[1:])
+	if err != nil {
+		return err
+	}
+	return editTags(cfg, args[0], func(existing []string) []string {
+		return tag.AddTags(existing, tags)
+	})
+}
+
+// TagRemoveCommand removes tags (wildcards allowed) from one or more workspaces.
+func TagRemoveCommand(cfg *config.Config, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: tag rm WORKSPACE[,WORKSPACE...] TAG...")
+	}
+	tags := args[1:]
+	return editTags(cfg, args[0], func(existing []string) []string {
+		return tag.RemoveTags(existing, tags)
+	})
+}
+
+// TagSetCommand replaces the tags of one or more workspaces.
+// Passing no tags clears them.
+func TagSetCommand(cfg *config.Config, args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: tag set WORKSPACE[,WORKSPACE...] [TAG...]")
+	}
+	tags, err := resolveTags(cfg, args[1:])
+	if err != nil {
+		return err
+	}
+	return editTags(cfg, args[0], func(existing []string) []string {
+		return tag.SetTags(existing, tags)
+	})
+}
+
+// TagRenameCommand renames a tag across every workspace in the root.
+func TagRenameCommand(cfg *config.Config, args []string, dryRun bool) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: tag rename OLD NEW")
+	}
+	oldTag, newTag := tag.Normalize(args[0]), tag.Normalize(args[1])
+	if oldTag == "" || newTag == "" {
+		return fmt.Errorf("tags must not be empty")
+	}
+	resolved, err := resolveTags(cfg, []string{newTag})
+	if err != nil {
+		return err
+	}
+	newTag = resolved[0]
+	return replaceTagsEverywhere(cfg, []string{oldTag}, newTag, dryRun)
+}
+
+// TagMergeCommand merges several tags into one across every workspace in the root.
+// args has the form "A B... INTO C".
+func TagMergeCommand(cfg *config.Config, args []string, dryRun bool) error {
+	into := -1
+	for i, arg := range args {
+		if strings.EqualFold(arg, "into") {
+			into = i
+			break
+		}
+	}
+	if into < 1 || into != len(args)-2 {
+		return fmt.Errorf("usage: tag merge A B... INTO C")
+	}
+
+	sources := tag.NormalizeAll(args[:into])
+	target := tag.Normalize(args[len(args)-1])
+	if target == "" {
+		return fmt.Errorf("target tag must not be empty")
+	}
+	resolved, err := resolveTags(cfg, []string{target})
+	if err != nil {
+		return err
+	}
+	target = resolved[0]
+	return replaceTagsEverywhere(cfg, sources, target, dryRun)
+}
+
+// replaceTagsEverywhere replaces the tags in from with to in every workspace.
+// Nothing is written if any ws_info.toml fails to parse.
+func replaceTagsEverywhere(cfg *config.Config, from []string, to string, dryRun bool) error {
+	workspaces, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	updates, err := tag.PlanTagUpdates(workspaces, func(existing []string) []string {
+		return tag.ReplaceTags(existing, from, to)
+	})
+	if err != nil {
+		return fmt.Errorf("%w; no files were written", err)
+	}
+
+	changed := 0
+	for _, update := range updates {
+		if update.Changed() {
+			printTagUpdate(update)
+			changed++
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("Dry run: %d workspace(s) would change; no files were written.\n", changed)
+		return nil
+	}
+
+	if err := tag.ApplyTagUpdates(updates); err != nil {
+		return err
+	}
+	fmt.Printf("%d workspace(s) changed.\n", changed)
+	return nil
+}
+
+// TagLintCommand checks the tags of every workspace against the configured
+// vocabulary. Tags that are not normalized or that are synonyms of a
+// canonical tag are rewritten when fix is true; unknown tags are only reported.
+func TagLintCommand(cfg *config.Config, fix bool) error {
+	workspaces, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	registry := tag.NewRegistry(cfg.Tags)
+	problems := 0
+	var fixable []workspace.Workspace
+
+	for _, ws := range workspaces {
+		workspaceName := ws.Name
+		wsInfoPath := filepath.Join(ws.Path, "ws_info.toml")
+		info, err := workspace.ParseWSInfo(wsInfoPath)
+		if err != nil {
+			fmt.Printf("%s: failed to parse %s: %v\n", workspaceName, wsInfoPath, err)
+			problems++
+			continue
+		}
+
+		needsFix := false
+		for _, t := range info.Info.Tags {
+			resolved, err := registry.Resolve(t)
+			switch {
+			case err != nil:
+				fmt.Printf("%s: %v\n", workspaceName, err)
+				problems++
+			case resolved != t:
+				fmt.Printf("%s: tag '%s' should be '%s'\n", workspaceName, t, resolved)
+				problems++
+				needsFix = true
+			}
+		}
+		if needsFix {
+			fixable = append(fixable, ws)
+		}
+	}
+
+	for _, cycle := range tag.FindCycles(cfg.Tags.Implications) {
+		fmt.Printf("config: implication rules form a cycle: %s\n", strings.Join(cycle, " -> "))
+		problems++
+	}
+
+	if problems == 0 {
+		fmt.Println("All tags are valid.")
+		return nil
+	}
+
+	if fix && len(fixable) > 0 {
+		updates, err := tag.UpdateTags(fixable, func(existing []string) []string {
+			result := make([]string, 0, len(existing))
+			for _, t := range existing {
+				resolved, _ := registry.Resolve(t)
+				result = append(result, resolved)
+			}
+			return tag.NormalizeAll(result)
+		})
+		for _, update := range updates {
+			printTagUpdate(update)
+		}
+		if err != nil {
+			return err
+		}
+		problems -= countFixed(updates, registry)
+		if problems == 0 {
+			fmt.Println("All problems fixed.")
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%d tag problem(s) found", problems)
+}
+
+// TagRulesCommand prints the configured tag implication rules and any cycles among them.
+func TagRulesCommand(cfg *config.Config) error {
+	rules := cfg.Tags.Implications
+	if len(rules) == 0 {
+		fmt.Println("No tag implication rules configured.")
+		return nil
+	}
+
+	fmt.Println("Tag implication rules:")
+	for _, rule := range rules {
+		fmt.Printf("  %s => %s\n", rule.If, strings.Join(rule.Then, ", "))
+	}
+
+	cycles := tag.FindCycles(rules)
+	if len(cycles) == 0 {
+		return nil
+	}
+	fmt.Println("Cycles:")
+	for _, cycle := range cycles {
+		fmt.Printf("  %s\n", strings.Join(cycle, " -> "))
+	}
+	return fmt.Errorf("%d cycle(s) in tag implication rules", len(cycles))
+}
+
+// countFixed counts the tags that an applied lint fix rewrote.
+func countFixed(updates []tag.TagUpdate, registry *tag.Registry) int {
+	fixed := 0
+	for _, update := range updates {
+		for _, t := range update.Before {
+			if resolved, err := registry.Resolve(t); err == nil && resolved != t {
+				fixed++
+			}
+		}
+	}
+	return fixed
+}
+
+// resolveTags checks tags against the configured tag vocabulary, mapping
+// synonyms to canonical tags. Unknown tags are an error in strict mode and
+// a warning otherwise.
+func resolveTags(cfg *config.Config, tags []string) ([]string, error) {
+	registry := tag.NewRegistry(cfg.Tags)
+	resolved, problems := registry.ResolveAll(tags)
+	if len(problems) > 0 && registry.Strict() {
+		return nil, errors.Join(problems...)
+	}
+	for _, problem := range problems {
+		slog.Warn(problem.Error())
+	}
+	return resolved, nil
+}
+
+// editTags resolves a comma-separated workspace list, applies edit to each
+// workspace's tags and prints the result.
+func editTags(cfg *config.Config, workspaceList string, edit func([]string) []string) error {
+	workspaces, err := resolveWorkspaceList(cfg, workspaceList)
+	if err != nil {
+		return err
+	}
+
+	updates, err := tag.UpdateTags(workspaces, edit)
+	for _, update := range updates {
+		printTagUpdate(update)
+	}
+	return err
+}
+
+// resolveWorkspaceList turns "a,b,c" into workspaces, checking that each
+// names a workspace (see workspace.Find).
+func resolveWorkspaceList(cfg *config.Config, workspaceList string) ([]workspace.Workspace, error) {
+	all, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	var workspaces []workspace.Workspace
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(workspaceList, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		ws, err := workspace.Find(all, name)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[ws.Path] {
+			seen[ws.Path] = true
+			workspaces = append(workspaces, ws)
+		}
+	}
+
+	if len(workspaces) == 0 {
+		return nil, fmt.Errorf("at least one workspace is required")
+	}
+	return workspaces, nil
+}
+
+// printTagUpdate prints the tags of a workspace after an edit.
+func printTagUpdate(update tag.TagUpdate) {
+	workspaceName := update.Workspace.Name
+	if !update.Changed() {
+		fmt.Printf("%s: unchanged [%s]\n", workspaceName, strings.Join(update.After, ", "))
+		return
+	}
+	fmt.Printf("%s: [%s] -> [%s]\n", workspaceName, strings.Join(update.Before, ", "), strings.Join(update.After, ", "))
+}
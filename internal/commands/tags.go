@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/tag"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// TagsCommand prints every tag in use with its workspace count, total disk size,
+// and the workspaces using it. sortBy is one of "count", "name", or "size".
+// When withSize is false the (slow) disk usage walk is skipped. When tree is
+// true, hierarchical tags are printed as a tree instead of a table.
+func TagsCommand(cfg *config.Config, sortBy string, withSize bool, tree bool) error {
+	workspaces, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	if len(workspaces) == 0 {
+		fmt.Println("No valid workspaces found.")
+		return nil
+	}
+
+	if sortBy == "size" && !withSize {
+		return fmt.Errorf("cannot sort by size when sizes are not computed")
+	}
+
+	tagsByWorkspace := make(map[string][]string)
+	sizes := make(map[string]int64)
+	for _, ws := range workspaces {
+		workspacePath := ws.Path
+		info, err := tag.Load(workspacePath, cfg.Tags.Implications)
+		if err != nil {
+			slog.Warn("failed to parse ws_info.toml", "path", filepath.Join(workspacePath, "ws_info.toml"), "error", err)
+			continue
+		}
+		if len(info.EffectiveTags) == 0 {
+			continue
+		}
+
+		workspaceName := ws.Name
+		tagsByWorkspace[workspaceName] = info.EffectiveTags
+
+		if withSize {
+			size, err := workspace.GetWorkspaceSize(workspacePath)
+			if err != nil {
+				slog.Warn("failed to calculate workspace size", "workspace", workspaceName, "error", err)
+				continue
+			}
+			sizes[workspaceName] = size
+		}
+	}
+
+	stats := tag.Aggregate(tagsByWorkspace, sizes)
+	if len(stats) == 0 {
+		fmt.Println("No tags found in any workspace.")
+		return nil
+	}
+	if err := tag.SortStats(stats, sortBy); err != nil {
+		return err
+	}
+
+	if tree {
+		printTagTree(tag.BuildTree(stats, sizes), "", withSize)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if withSize {
+		fmt.Fprintln(w, "TAG\tCOUNT\tSIZE\tWORKSPACES")
+	} else {
+		fmt.Fprintln(w, "TAG\tCOUNT\tWORKSPACES")
+	}
+	for _, stat := range stats {
+		if withSize {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", stat.Tag, stat.Count(), formatBytes(stat.Size), strings.Join(stat.Workspaces, ", "))
+		} else {
+			fmt.Fprintf(w, "%s\t%d\t%s\n", stat.Tag, stat.Count(), strings.Join(stat.Workspaces, ", "))
+		}
+	}
+	return w.Flush()
+}
+
+// printTagTree prints the tag hierarchy with per-node workspace counts.
+func printTagTree(nodes []*tag.TreeNode, indent string, withSize bool) {
+	for i, node := range nodes {
+		branch, childIndent := "├── ", indent+"│   "
+		if i == len(nodes)-1 {
+			branch, childIndent = "└── ", indent+"    "
+		}
+
+		if withSize {
+			fmt.Printf("%s%s%s (%d, %s)\n", indent, branch, node.Segment, node.Stat.Count(), formatBytes(node.Stat.Size))
+		} else {
+			fmt.Printf("%s%s%s (%d)\n", indent, branch, node.Segment, node.Stat.Count())
+		}
+		printTagTree(node.Children, childIndent, withSize)
+	}
+}
+
+// AllTags returns every distinct effective tag used across the workspaces in the root,
+// sorted by name. Workspaces whose ws_info.toml cannot be parsed are skipped.
+func AllTags(cfg *config.Config) ([]string, error) {
+	workspaces, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	var all []string
+	for _, ws := range workspaces {
+		workspacePath := ws.Path
+		info, err := tag.Load(workspacePath, cfg.Tags.Implications)
+		if err != nil {
+			continue
+		}
+		all = append(all, info.EffectiveTags...)
+	}
+
+	all = tag.NormalizeAll(all)
+	sort.Strings(all)
+	return all, nil
+}
@@ -0,0 +1,152 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// WhereOptions controls WhereCommand.
+type WhereOptions struct {
+	// All prints every match instead of failing when the query is ambiguous.
+	All bool
+	// JSON prints matches as JSON: an object, or an array with All.
+	JSON bool
+}
+
+// WhereMatch is one workspace found by WhereCommand.
+type WhereMatch struct {
+	Name  string `json:"name"`
+	Path  string `json:"path"`
+	Match string `json:"match"` // "alias", "name", or "prefix"
+	Alias string `json:"alias,omitempty"`
+}
+
+// WhereCommand prints the absolute path of the workspace that args[0] names.
+// The query is tried as an alias, then as an exact workspace name, then as a
+// prefix of workspace names, where a name also matches by its last element;
+// the first kind that matches anything wins. A prefix must be unique unless
+// opts.All is set, in which case every match of every kind is printed. It
+// never prompts.
+func WhereCommand(cfg *config.Config, args []string, opts WhereOptions) error {
+	if len(args) < 1 {
+		return fmt.Errorf("alias or workspace name is required")
+	}
+	query := args[0]
+
+	matches, err := findWorkspaces(cfg, query, opts.All)
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return fmt.Errorf("no alias or workspace matches '%s'", query)
+	}
+	if !opts.All && len(matches) > 1 {
+		names := make([]string, len(matches))
+		for i, m := range matches {
+			names[i] = m.Name
+		}
+		return fmt.Errorf("'%s' is ambiguous: %s (use --all to list them)", query, strings.Join(names, ", "))
+	}
+
+	if opts.JSON {
+		var v interface{} = matches[0]
+		if opts.All {
+			v = matches
+		}
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, m := range matches {
+		fmt.Println(m.Path)
+	}
+	return nil
+}
+
+// findWorkspaces returns the workspaces matching query as an alias, an exact
+// name, or a name prefix. Unless all is set, it stops at the first kind of
+// match that finds anything.
+func findWorkspaces(cfg *config.Config, query string, all bool) ([]WhereMatch, error) {
+	workspaces, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	var matches []WhereMatch
+	seen := make(map[string]bool)
+	add := func(m WhereMatch) {
+		if !seen[m.Path] {
+			seen[m.Path] = true
+			matches = append(matches, m)
+		}
+	}
+
+	// Collisions between other aliases are none of this query's business, so
+	// the error policy only applies to the queried alias itself.
+	definitions := workspace.CollectAliases(workspaces)
+	policy := cfg.Aliases.CollisionPolicy
+	if policy == workspace.CollisionError {
+		for _, c := range workspace.FindAliasCollisions(definitions) {
+			if c.Alias == query && !all {
+				return nil, &workspace.AliasCollisionError{Collisions: []workspace.AliasCollision{c}}
+			}
+		}
+		policy = workspace.CollisionFirstWins
+	}
+	aliases, _, err := workspace.ResolveAliasDefinitions(definitions, policy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aliases: %w", err)
+	}
+	var aliasDefs []workspace.AliasDefinition
+	if def, ok := aliases[query]; ok {
+		aliasDefs = append(aliasDefs, def)
+	}
+	if all {
+		for _, def := range definitions {
+			if def.Alias == query {
+				aliasDefs = append(aliasDefs, def)
+			}
+		}
+	}
+	for _, def := range aliasDefs {
+		path, err := filepath.Abs(def.WorkspacePath)
+		if err != nil {
+			return nil, err
+		}
+		add(WhereMatch{Name: def.WorkspaceName, Path: path, Match: "alias", Alias: query})
+	}
+	if len(matches) > 0 && !all {
+		return matches, nil
+	}
+
+	for _, kind := range []string{"name", "prefix"} {
+		for _, ws := range workspaces {
+			base := path.Base(ws.Rel)
+			if kind == "name" && ws.Name != query && ws.Rel != query && ws.QualifiedName() != query && base != query {
+				continue
+			}
+			if kind == "prefix" && !strings.HasPrefix(ws.Name, query) && !strings.HasPrefix(ws.Rel, query) && !strings.HasPrefix(base, query) {
+				continue
+			}
+			abs, err := filepath.Abs(ws.Path)
+			if err != nil {
+				return nil, err
+			}
+			add(WhereMatch{Name: ws.Name, Path: abs, Match: kind})
+		}
+		if len(matches) > 0 && !all {
+			break
+		}
+	}
+	return matches, nil
+}
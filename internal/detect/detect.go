@@ -0,0 +1,127 @@
+package detect
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/johnjallday/GoTagManager/config"
+)
+
+// Detector inspects a workspace directory and suggests tags for it.
+type Detector interface {
+	// Name identifies the detector in diagnostics.
+	Name() string
+	// Detect returns the tags suggested for the workspace at workspacePath.
+	Detect(workspacePath string) ([]string, error)
+}
+
+// GlobDetector suggests Tags when any path matching Glob exists in the
+// workspace. Glob is relative to the workspace root and may contain '/'
+// to look into subdirectories, e.g. "src/*.py".
+type GlobDetector struct {
+	Glob string
+	Tags []string
+}
+
+// Name returns the glob the detector looks for.
+func (d GlobDetector) Name() string {
+	return d.Glob
+}
+
+// Detect returns d.Tags if the glob matches anything in the workspace.
+func (d GlobDetector) Detect(workspacePath string) ([]string, error) {
+	found, err := globExists(workspacePath, strings.Split(filepath.ToSlash(d.Glob), "/"))
+	if err != nil || !found {
+		return nil, err
+	}
+	return d.Tags, nil
+}
+
+// DefaultDetectors are the built-in file-to-tag mappings.
+var DefaultDetectors = []Detector{
+	GlobDetector{Glob: "go.mod", Tags: []string{"lang/go"}},
+	GlobDetector{Glob: "package.json", Tags: []string{"lang/js"}},
+	GlobDetector{Glob: "tsconfig.json", Tags: []string{"lang/ts"}},
+	GlobDetector{Glob: "Cargo.toml", Tags: []string{"lang/rust"}},
+	GlobDetector{Glob: "pyproject.toml", Tags: []string{"lang/python"}},
+	GlobDetector{Glob: "requirements*.txt", Tags: []string{"lang/python"}},
+	GlobDetector{Glob: "Gemfile", Tags: []string{"lang/ruby"}},
+	GlobDetector{Glob: "pom.xml", Tags: []string{"lang/java"}},
+	GlobDetector{Glob: "build.gradle*", Tags: []string{"lang/java"}},
+	GlobDetector{Glob: "*.csproj", Tags: []string{"lang/csharp"}},
+	GlobDetector{Glob: "composer.json", Tags: []string{"lang/php"}},
+	GlobDetector{Glob: ".git", Tags: []string{"vcs/git"}},
+	GlobDetector{Glob: ".hg", Tags: []string{"vcs/hg"}},
+	GlobDetector{Glob: "Dockerfile", Tags: []string{"docker"}},
+	GlobDetector{Glob: "docker-compose.y*ml", Tags: []string{"docker"}},
+	GlobDetector{Glob: "compose.y*ml", Tags: []string{"docker"}},
+}
+
+// Detectors returns the detectors configured under [autotag]: the built-in
+// detectors (unless disabled) followed by one GlobDetector per configured rule.
+func Detectors(cfg config.AutoTag) []Detector {
+	var detectors []Detector
+	if !cfg.DisableDefaults {
+		detectors = append(detectors, DefaultDetectors...)
+	}
+	for _, rule := range cfg.Rules {
+		detectors = append(detectors, GlobDetector{Glob: rule.Glob, Tags: rule.Tags})
+	}
+	return detectors
+}
+
+// Detect runs every detector against the workspace and returns the sorted,
+// deduplicated union of their suggestions.
+func Detect(workspacePath string, detectors []Detector) ([]string, error) {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, d := range detectors {
+		suggested, err := d.Detect(workspacePath)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range suggested {
+			if !seen[t] {
+				seen[t] = true
+				tags = append(tags, t)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// globExists reports whether any path below dir matches the glob segments.
+// Segments are matched one directory level at a time, so special characters
+// in dir itself are never interpreted as glob syntax.
+func globExists(dir string, segments []string) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) || os.IsPermission(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, entry := range entries {
+		matched, err := filepath.Match(segments[0], entry.Name())
+		if err != nil {
+			return false, err
+		}
+		if !matched {
+			continue
+		}
+		if len(segments) == 1 {
+			return true, nil
+		}
+		if entry.IsDir() {
+			found, err := globExists(filepath.Join(dir, entry.Name()), segments[1:])
+			if err != nil || found {
+				return found, err
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,66 @@
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Score ranks how well query matches candidate, ignoring case. Every rune of
+// query must appear in candidate in order; ok is false otherwise. Exact
+// matches beat prefixes, prefixes beat substrings, and substrings beat
+// scattered subsequences. Within each class, runs of consecutive runes and
+// matches at word starts (after '/', '-', '_', '.', ':' or a space) score
+// higher, and longer candidates score slightly lower.
+func Score(query, candidate string) (score int, ok bool) {
+	q := []rune(strings.ToLower(query))
+	c := []rune(strings.ToLower(candidate))
+	if len(q) == 0 || len(q) > len(c) {
+		return 0, false
+	}
+
+	lengthPenalty := len(c) - len(q)
+	switch idx := strings.Index(string(c), string(q)); {
+	case lengthPenalty == 0 && idx == 0:
+		return 1000, true
+	case idx == 0:
+		return 800 - lengthPenalty, true
+	case idx > 0:
+		score = 600 - lengthPenalty
+		if isBoundary(c, len([]rune(string(c)[:idx]))) {
+			score += 50
+		}
+		return score, true
+	}
+
+	// Greedy subsequence match.
+	score = 200 - lengthPenalty
+	qi, prev := 0, -2
+	for ci := 0; ci < len(c) && qi < len(q); ci++ {
+		if c[ci] != q[qi] {
+			continue
+		}
+		switch {
+		case ci == prev+1:
+			score += 10
+		case isBoundary(c, ci):
+			score += 8
+		default:
+			score -= ci - prev - 1
+		}
+		prev = ci
+		qi++
+	}
+	if qi < len(q) {
+		return 0, false
+	}
+	return score, true
+}
+
+// isBoundary reports whether the rune at i starts a word.
+func isBoundary(runes []rune, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev := runes[i-1]
+	return unicode.IsSpace(prev) || strings.ContainsRune("/-_.:", prev)
+}
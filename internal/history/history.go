@@ -0,0 +1,185 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/johnjallday/GoTagManager/internal/fileutil"
+)
+
+// maxRank bounds the total rank of all entries. Once it is exceeded every
+// rank is scaled down, so old habits fade and the file stays small.
+const maxRank = 2000
+
+// Entry records how often and how recently a workspace was visited.
+type Entry struct {
+	Path      string    `json:"path"`
+	Rank      float64   `json:"rank"`
+	Visits    int       `json:"visits"`
+	LastVisit time.Time `json:"last_visit"`
+}
+
+// Frecency combines the entry's rank with how recently it was visited,
+// weighting visits within the last hour four times as much as a plain
+// visit and those older than a week a quarter as much.
+func (e Entry) Frecency(now time.Time) float64 {
+	switch age := now.Sub(e.LastVisit); {
+	case age < time.Hour:
+		return e.Rank * 4
+	case age < 24*time.Hour:
+		return e.Rank * 2
+	case age < 7*24*time.Hour:
+		return e.Rank / 2
+	default:
+		return e.Rank / 4
+	}
+}
+
+// History is the visit history stored in a JSON file.
+type History struct {
+	path    string
+	entries map[string]*Entry
+}
+
+// DefaultPath returns the history file under $XDG_STATE_HOME/gotagmanager,
+// falling back to ~/.local/state/gotagmanager.
+func DefaultPath() (string, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to locate state directory: %w", err)
+		}
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateDir, "gotagmanager", "history.json"), nil
+}
+
+// New returns an empty history that Save writes to path.
+func New(path string) *History {
+	return &History{path: path, entries: make(map[string]*Entry)}
+}
+
+// Load reads the history file at path. A missing file is an empty history.
+func Load(path string) (*History, error) {
+	h := New(path)
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, e := range entries {
+		h.entries[e.Path] = e
+	}
+	return h, nil
+}
+
+// Open loads the history file at DefaultPath.
+func Open() (*History, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return Load(path)
+}
+
+// Entry returns the entry for a workspace path, if it has been visited.
+func (h *History) Entry(path string) (Entry, bool) {
+	e, ok := h.entries[path]
+	if !ok {
+		return Entry{}, false
+	}
+	return *e, true
+}
+
+// Entries returns every entry sorted by path.
+func (h *History) Entries() []Entry {
+	entries := make([]Entry, 0, len(h.entries))
+	for _, e := range h.entries {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries
+}
+
+// Frecency returns the frecency of a workspace path, or 0 if it has never
+// been visited.
+func (h *History) Frecency(path string, now time.Time) float64 {
+	e, ok := h.entries[path]
+	if !ok {
+		return 0
+	}
+	return e.Frecency(now)
+}
+
+// Visit records a visit to a workspace path at now.
+func (h *History) Visit(path string, now time.Time) {
+	e, ok := h.entries[path]
+	if !ok {
+		e = &Entry{Path: path}
+		h.entries[path] = e
+	}
+	e.Rank++
+	e.Visits++
+	e.LastVisit = now
+
+	var total float64
+	for _, e := range h.entries {
+		total += e.Rank
+	}
+	if total > maxRank {
+		for p, e := range h.entries {
+			e.Rank *= 0.9
+			if e.Rank < 1 {
+				delete(h.entries, p)
+			}
+		}
+	}
+}
+
+// Save writes the history back to its file atomically.
+func (h *History) Save() error {
+	entries := make([]*Entry, 0, len(h.entries))
+	for _, e := range h.entries {
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+	return fileutil.WriteFileAtomic(h.path, 0o644, func(f *os.File) error {
+		_, err := f.Write(data)
+		return err
+	})
+}
+
+// Record adds a visit to a workspace path to the default history file.
+func Record(path string) error {
+	h, err := Open()
+	if err != nil {
+		return err
+	}
+	h.Visit(path, time.Now())
+	if err := h.Save(); err != nil {
+		return fmt.Errorf("failed to save history: %w", err)
+	}
+	return nil
+}
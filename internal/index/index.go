@@ -0,0 +1,195 @@
+package index
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/fileutil"
+	"github.com/johnjallday/GoTagManager/internal/tag"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// Entry is the cached ws_info.toml data of one workspace.
+type Entry struct {
+	Name          string    `json:"name"`
+	Path          string    `json:"path"` // absolute, with symlinks resolved
+	Tags          []string  `json:"tags"`
+	EffectiveTags []string  `json:"effective_tags"`
+	Aliases       []string  `json:"aliases"`
+	ModTime       time.Time `json:"mod_time"` // of ws_info.toml when cached
+}
+
+// Index caches the parsed ws_info.toml of every workspace under a root so
+// that frequently run commands such as prompt-segment need not parse TOML.
+// It is only valid for the roots and implication rules it was built with.
+type Index struct {
+	Roots      []config.Root           `json:"roots"`
+	Rules      []config.TagImplication `json:"rules"`
+	Workspaces map[string]Entry        `json:"workspaces"` // keyed by Path
+
+	path string
+}
+
+// DefaultPath returns the index file for cfg under the user cache
+// directory, e.g. $XDG_CACHE_HOME/gotagmanager/index-1a2b3c4d5e6f.json. The
+// name is derived from the roots and implication rules, so profiles with
+// different settings keep separate indexes instead of rebuilding a shared
+// one whenever the profile changes.
+func DefaultPath(cfg *config.Config) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	key, err := json.Marshal(struct {
+		Roots []config.Root
+		Rules []config.TagImplication
+	}{cfg.Roots, cfg.Tags.Implications})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(key)
+	name := fmt.Sprintf("index-%x.json", sum[:6])
+	return filepath.Join(cacheDir, "gotagmanager", name), nil
+}
+
+// Open returns the index at DefaultPath for cfg, rebuilding and saving it
+// if it is missing, unreadable, or was built for other roots or other
+// implication rules.
+func Open(cfg *config.Config) (*Index, error) {
+	path, err := DefaultPath(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	ix, err := load(path)
+	if err == nil && reflect.DeepEqual(ix.Roots, cfg.Roots) && reflect.DeepEqual(ix.Rules, cfg.Tags.Implications) {
+		return ix, nil
+	}
+
+	ix, err = Build(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ix.path = path
+	return ix, ix.Save()
+}
+
+// Rebuild builds a fresh index for cfg and saves it at DefaultPath.
+func Rebuild(cfg *config.Config) (*Index, error) {
+	path, err := DefaultPath(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ix, err := Build(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ix.path = path
+	return ix, ix.Save()
+}
+
+// Build parses every workspace in cfg.Roots. Workspaces whose ws_info.toml
+// fails to parse are left out.
+func Build(cfg *config.Config) (*Index, error) {
+	workspaces, err := workspace.ListAll(cfg.Roots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+
+	ix := &Index{
+		Roots:      cfg.Roots,
+		Rules:      cfg.Tags.Implications,
+		Workspaces: make(map[string]Entry, len(workspaces)),
+	}
+	for _, ws := range workspaces {
+		workspacePath := ws.Path
+		if resolved, err := filepath.EvalSymlinks(workspacePath); err == nil {
+			workspacePath = resolved
+		}
+		if entry, err := newEntry(ws.Name, workspacePath, cfg.Tags.Implications); err == nil {
+			ix.Workspaces[entry.Path] = entry
+		}
+	}
+	return ix, nil
+}
+
+// Lookup returns the entry for a workspace whose path is absolute with
+// symlinks resolved, as returned by workspace.FindEnclosingAll. Its
+// ws_info.toml is re-read if the workspace is new or the file changed since
+// it was cached, and the index is then saved.
+func (ix *Index) Lookup(ws workspace.Workspace) (Entry, error) {
+	path := ws.Path
+	wsInfo, err := os.Stat(filepath.Join(path, "ws_info.toml"))
+	if err != nil {
+		return Entry{}, err
+	}
+	if entry, ok := ix.Workspaces[path]; ok && entry.ModTime.Equal(wsInfo.ModTime()) {
+		return entry, nil
+	}
+
+	entry, err := newEntry(ws.Name, path, ix.Rules)
+	if err != nil {
+		return Entry{}, err
+	}
+	ix.Workspaces[path] = entry
+	return entry, ix.Save()
+}
+
+// Save writes the index to its file atomically.
+func (ix *Index) Save() error {
+	data, err := json.Marshal(ix)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ix.path), 0o755); err != nil {
+		return err
+	}
+	return fileutil.WriteFileAtomic(ix.path, 0o644, func(f *os.File) error {
+		_, err := f.Write(data)
+		return err
+	})
+}
+
+// load reads the index file at path.
+func load(path string) (*Index, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var ix Index
+	if err := json.Unmarshal(data, &ix); err != nil {
+		return nil, err
+	}
+	if ix.Workspaces == nil {
+		return nil, errors.New("index has no workspaces table")
+	}
+	ix.path = path
+	return &ix, nil
+}
+
+// newEntry parses the ws_info.toml of the workspace at path.
+func newEntry(name, path string, rules []config.TagImplication) (Entry, error) {
+	stat, err := os.Stat(filepath.Join(path, "ws_info.toml"))
+	if err != nil {
+		return Entry{}, err
+	}
+	info, err := tag.Load(path, rules)
+	if err != nil {
+		return Entry{}, err
+	}
+	return Entry{
+		Name:          name,
+		Path:          path,
+		Tags:          info.Info.Tags,
+		EffectiveTags: info.EffectiveTags,
+		Aliases:       workspace.AliasNames(info.Info.Aliases),
+		ModTime:       stat.ModTime(),
+	}, nil
+}
@@ -0,0 +1,47 @@
+package shell
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Action describes what running an alias does. Paths are absolute.
+type Action struct {
+	Dir  string            // directory to cd into
+	Venv string            // Python virtualenv directory to activate
+	Env  map[string]string // environment variables to export
+	Run  string            // command to run afterwards, in the target shell's syntax
+	Tmux string            // tmux session to create or attach to, started in Dir
+	Hook []string          // command run quietly after the cd, e.g. to record the visit
+}
+
+// IsPlain reports whether the action is a bare cd, which every shell can
+// express as a simple alias.
+func (a Action) IsPlain() bool {
+	return a.Venv == "" && len(a.Env) == 0 && a.Run == "" && a.Tmux == "" && len(a.Hook) == 0
+}
+
+// envNamePattern matches portable environment variable names.
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateAction checks the parts of an action that are interpolated into
+// generated code. Values are always quoted; names cannot be, so they must
+// be plain identifiers.
+func ValidateAction(a Action) error {
+	for _, name := range sortedKeys(a.Env) {
+		if !envNamePattern.MatchString(name) {
+			return fmt.Errorf("environment variable name '%s' is not a valid identifier", name)
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,271 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Emitter renders workspace aliases in the syntax of one shell.
+type Emitter interface {
+	// Name is the shell name accepted by --shell.
+	Name() string
+	// Alias returns the definition that makes name perform action. A plain
+	// action becomes the shell's simplest cd alias; anything richer becomes
+	// a function.
+	Alias(name string, action Action) string
+	// Jump returns a function called name that runs command with the
+	// function's arguments appended and cds into the directory it prints.
+	Jump(name string, command []string) string
+	// Reserved lists the builtins and keywords an alias must not shadow.
+	Reserved() []string
+	// Source returns the statement that loads the script at path.
+	Source(path string) string
+	// Extension is the file extension of scripts for the shell, e.g. ".zsh".
+	Extension() string
+	// RCFile is the default startup file of the shell, relative to $HOME.
+	RCFile() string
+}
+
+var emitters = make(map[string]Emitter)
+
+// shellAliases maps alternative spellings to registered shell names.
+var shellAliases = map[string]string{
+	"nu":   "nushell",
+	"pwsh": "powershell",
+	"sh":   "bash",
+}
+
+// Register makes an emitter available under its name.
+func Register(e Emitter) {
+	emitters[e.Name()] = e
+}
+
+// Lookup returns the emitter for a shell name such as "zsh" or "fish".
+func Lookup(name string) (Emitter, error) {
+	name = strings.ToLower(name)
+	if canonical, ok := shellAliases[name]; ok {
+		name = canonical
+	}
+	e, ok := emitters[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported shell '%s' (supported: %s)", name, strings.Join(Names(), ", "))
+	}
+	return e, nil
+}
+
+// Detect guesses the shell from the name of an rc file such as ".zshrc" or
+// "config.fish", falling back to the basename of $SHELL and then to zsh.
+func Detect(rcFile string) Emitter {
+	base := strings.ToLower(filepath.Base(rcFile))
+	for _, name := range Names() {
+		e := emitters[name]
+		if rcFile != "" && (base == strings.ToLower(filepath.Base(e.RCFile())) || strings.HasSuffix(base, e.Extension())) {
+			return e
+		}
+	}
+	if e, err := Lookup(filepath.Base(os.Getenv("SHELL"))); err == nil {
+		return e
+	}
+	return emitters["zsh"]
+}
+
+// Names returns the registered shell names in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(emitters))
+	for name := range emitters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register(posixEmitter{name: "zsh"})
+	Register(posixEmitter{name: "bash"})
+	Register(fishEmitter{})
+	Register(nuEmitter{})
+	Register(powerShellEmitter{})
+}
+
+// posixEmitter writes bash/zsh aliases whose value is a quoted cd command.
+type posixEmitter struct{ name string }
+
+func (e posixEmitter) Name() string { return e.name }
+
+func (e posixEmitter) Reserved() []string {
+	if e.name == "zsh" {
+		return zshReserved
+	}
+	return bashReserved
+}
+
+func (e posixEmitter) Source(path string) string {
+	return "source " + QuotePOSIX(path)
+}
+
+func (e posixEmitter) Extension() string { return "." + e.name }
+
+func (e posixEmitter) RCFile() string { return "." + e.name + "rc" }
+
+func (e posixEmitter) Alias(name string, a Action) string {
+	if a.IsPlain() {
+		return fmt.Sprintf("alias %s=%s", name, QuotePOSIX("cd "+QuotePOSIX(a.Dir)))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s() {\n", name)
+	fmt.Fprintf(&b, "    cd -- %s || return\n", QuotePOSIX(a.Dir))
+	if len(a.Hook) > 0 {
+		// The subshell keeps job control from announcing the background job
+		fmt.Fprintf(&b, "    (command %s >/dev/null 2>&1 &)\n", quoteAll(a.Hook, QuotePOSIX))
+	}
+	for _, k := range sortedKeys(a.Env) {
+		fmt.Fprintf(&b, "    export %s=%s\n", k, QuotePOSIX(a.Env[k]))
+	}
+	if a.Venv != "" {
+		fmt.Fprintf(&b, "    . %s\n", QuotePOSIX(filepath.Join(a.Venv, "bin", "activate")))
+	}
+	if a.Run != "" {
+		fmt.Fprintf(&b, "    %s\n", a.Run)
+	}
+	if a.Tmux != "" {
+		fmt.Fprintf(&b, "    tmux new-session -A -s %s -c %s\n", QuotePOSIX(a.Tmux), QuotePOSIX(a.Dir))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// fishEmitter writes fish functions, which run in the calling shell and can
+// therefore change its directory.
+type fishEmitter struct{}
+
+func (fishEmitter) Name() string { return "fish" }
+
+func (fishEmitter) Reserved() []string { return fishReserved }
+
+func (fishEmitter) Source(path string) string { return "source " + QuoteFish(path) }
+
+func (fishEmitter) Extension() string { return ".fish" }
+
+func (fishEmitter) RCFile() string { return ".config/fish/config.fish" }
+
+func (fishEmitter) Alias(name string, a Action) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "function %s --description %s\n", name, QuoteFish("cd to "+a.Dir))
+	if a.IsPlain() {
+		fmt.Fprintf(&b, "    cd %s\n", QuoteFish(a.Dir))
+	} else {
+		fmt.Fprintf(&b, "    cd %s; or return\n", QuoteFish(a.Dir))
+	}
+	if len(a.Hook) > 0 {
+		fmt.Fprintf(&b, "    command %s >/dev/null 2>&1 &; disown\n", quoteAll(a.Hook, QuoteFish))
+	}
+	for _, k := range sortedKeys(a.Env) {
+		fmt.Fprintf(&b, "    set -gx %s %s\n", k, QuoteFish(a.Env[k]))
+	}
+	if a.Venv != "" {
+		fmt.Fprintf(&b, "    source %s\n", QuoteFish(filepath.Join(a.Venv, "bin", "activate.fish")))
+	}
+	if a.Run != "" {
+		fmt.Fprintf(&b, "    %s\n", a.Run)
+	}
+	if a.Tmux != "" {
+		fmt.Fprintf(&b, "    tmux new-session -A -s %s -c %s\n", QuoteFish(a.Tmux), QuoteFish(a.Dir))
+	}
+	b.WriteString("end")
+	return b.String()
+}
+
+// nuEmitter writes nushell custom commands. --env lets the cd persist in
+// the caller's environment.
+type nuEmitter struct{}
+
+func (nuEmitter) Name() string { return "nushell" }
+
+func (nuEmitter) Reserved() []string { return nuReserved }
+
+func (nuEmitter) Source(path string) string { return "source " + QuoteNu(path) }
+
+func (nuEmitter) Extension() string { return ".nu" }
+
+func (nuEmitter) RCFile() string { return ".config/nushell/config.nu" }
+
+func (nuEmitter) Alias(name string, a Action) string {
+	if a.IsPlain() {
+		return fmt.Sprintf("def --env %s [] { cd %s }", name, QuoteNu(a.Dir))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "def --env %s [] {\n", name)
+	fmt.Fprintf(&b, "    cd %s\n", QuoteNu(a.Dir))
+	if len(a.Hook) > 0 {
+		fmt.Fprintf(&b, "    ^%s | complete | ignore\n", quoteAll(a.Hook, QuoteNu))
+	}
+	for _, k := range sortedKeys(a.Env) {
+		fmt.Fprintf(&b, "    $env.%s = %s\n", k, QuoteNu(a.Env[k]))
+	}
+	if a.Venv != "" {
+		// "overlay use" needs a parse-time constant, so activate by hand
+		fmt.Fprintf(&b, "    $env.VIRTUAL_ENV = %s\n", QuoteNu(a.Venv))
+		fmt.Fprintf(&b, "    $env.PATH = ($env.PATH | prepend %s)\n", QuoteNu(filepath.Join(a.Venv, "bin")))
+	}
+	if a.Run != "" {
+		fmt.Fprintf(&b, "    %s\n", a.Run)
+	}
+	if a.Tmux != "" {
+		fmt.Fprintf(&b, "    ^tmux new-session -A -s %s -c %s\n", QuoteNu(a.Tmux), QuoteNu(a.Dir))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// powerShellEmitter writes PowerShell functions around Set-Location.
+// -LiteralPath stops wildcard characters such as [ and ] in the path from
+// being expanded.
+type powerShellEmitter struct{}
+
+func (powerShellEmitter) Name() string { return "powershell" }
+
+func (powerShellEmitter) Reserved() []string { return powerShellReserved }
+
+// Source dot-sources the script so its functions land in the caller's scope.
+func (powerShellEmitter) Source(path string) string { return ". " + QuotePowerShell(path) }
+
+func (powerShellEmitter) Extension() string { return ".ps1" }
+
+func (powerShellEmitter) RCFile() string {
+	return ".config/powershell/Microsoft.PowerShell_profile.ps1"
+}
+
+func (powerShellEmitter) Alias(name string, a Action) string {
+	if a.IsPlain() {
+		return fmt.Sprintf("function %s { Set-Location -LiteralPath %s }", name, QuotePowerShell(a.Dir))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "function %s {\n", name)
+	fmt.Fprintf(&b, "    Set-Location -LiteralPath %s\n", QuotePowerShell(a.Dir))
+	if len(a.Hook) > 0 {
+		fmt.Fprintf(&b, "    & %s *> $null\n", quoteAll(a.Hook, QuotePowerShell))
+	}
+	for _, k := range sortedKeys(a.Env) {
+		fmt.Fprintf(&b, "    ${env:%s} = %s\n", k, QuotePowerShell(a.Env[k]))
+	}
+	if a.Venv != "" {
+		// Windows virtualenvs keep the script in Scripts, POSIX ones in bin
+		windows := filepath.Join(a.Venv, "Scripts", "Activate.ps1")
+		posix := filepath.Join(a.Venv, "bin", "Activate.ps1")
+		fmt.Fprintf(&b, "    if (Test-Path -LiteralPath %s) { . %s } else { . %s }\n", QuotePowerShell(windows), QuotePowerShell(windows), QuotePowerShell(posix))
+	}
+	if a.Run != "" {
+		fmt.Fprintf(&b, "    %s\n", a.Run)
+	}
+	if a.Tmux != "" {
+		fmt.Fprintf(&b, "    tmux new-session -A -s %s -c %s\n", QuotePowerShell(a.Tmux), QuotePowerShell(a.Dir))
+	}
+	b.WriteString("}")
+	return b.String()
+}
@@ -0,0 +1,208 @@
+package shell
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// trickyDir needs quoting in every shell: single and double quotes, a
+// variable reference, a backslash, a space, and PowerShell smart quotes.
+const trickyDir = `/w/it's "a" $b\c d ‘q’`
+
+var richAction = Action{
+	Dir:  trickyDir,
+	Venv: "/w/v env",
+	Env:  map[string]string{"NAME": `it's "x" $y`},
+	Run:  "echo hi",
+	Tmux: `s'1`,
+	Hook: []string{"/bin/gtm", "visit", trickyDir},
+}
+
+var jumpCommand = []string{"/opt/my gtm/gtm", "--config", "/c/it's.toml", "jump", "--"}
+
+func TestEmitterAlias(t *testing.T) {
+	posixRich := `x() {
+    cd -- '/w/it'\''s "a" $b\c d ‘q’' || return
+    (command '/bin/gtm' 'visit' '/w/it'\''s "a" $b\c d ‘q’' >/dev/null 2>&1 &)
+    export NAME='it'\''s "x" $y'
+    . '/w/v env/bin/activate'
+    echo hi
+    tmux new-session -A -s 's'\''1' -c '/w/it'\''s "a" $b\c d ‘q’'
+}`
+	tests := []struct {
+		shell string
+		plain string
+		rich  string
+	}{
+		{
+			shell: "bash",
+			plain: `alias x='cd '\''/w/it'\''\'\'''\''s "a" $b\c d ‘q’'\'''`,
+			rich:  posixRich,
+		},
+		{
+			shell: "zsh",
+			plain: `alias x='cd '\''/w/it'\''\'\'''\''s "a" $b\c d ‘q’'\'''`,
+			rich:  posixRich,
+		},
+		{
+			shell: "fish",
+			plain: `function x --description 'cd to /w/it\'s "a" $b\\c d ‘q’'
+    cd '/w/it\'s "a" $b\\c d ‘q’'
+end`,
+			rich: `function x --description 'cd to /w/it\'s "a" $b\\c d ‘q’'
+    cd '/w/it\'s "a" $b\\c d ‘q’'; or return
+    command '/bin/gtm' 'visit' '/w/it\'s "a" $b\\c d ‘q’' >/dev/null 2>&1 &; disown
+    set -gx NAME 'it\'s "x" $y'
+    source '/w/v env/bin/activate.fish'
+    echo hi
+    tmux new-session -A -s 's\'1' -c '/w/it\'s "a" $b\\c d ‘q’'
+end`,
+		},
+		{
+			shell: "nushell",
+			plain: `def --env x [] { cd "/w/it's \"a\" $b\\c d ‘q’" }`,
+			rich: `def --env x [] {
+    cd "/w/it's \"a\" $b\\c d ‘q’"
+    ^"/bin/gtm" "visit" "/w/it's \"a\" $b\\c d ‘q’" | complete | ignore
+    $env.NAME = "it's \"x\" $y"
+    $env.VIRTUAL_ENV = "/w/v env"
+    $env.PATH = ($env.PATH | prepend "/w/v env/bin")
+    echo hi
+    ^tmux new-session -A -s "s'1" -c "/w/it's \"a\" $b\\c d ‘q’"
+}`,
+		},
+		{
+			shell: "powershell",
+			plain: `function x { Set-Location -LiteralPath '/w/it''s "a" $b\c d ‘‘q’’' }`,
+			rich: `function x {
+    Set-Location -LiteralPath '/w/it''s "a" $b\c d ‘‘q’’'
+    & '/bin/gtm' 'visit' '/w/it''s "a" $b\c d ‘‘q’’' *> $null
+    ${env:NAME} = 'it''s "x" $y'
+    if (Test-Path -LiteralPath '/w/v env/Scripts/Activate.ps1') { . '/w/v env/Scripts/Activate.ps1' } else { . '/w/v env/bin/Activate.ps1' }
+    echo hi
+    tmux new-session -A -s 's''1' -c '/w/it''s "a" $b\c d ‘‘q’’'
+}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			e, err := Lookup(tt.shell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := e.Alias("x", Action{Dir: trickyDir}); got != tt.plain {
+				t.Errorf("plain alias:\n got: %s\nwant: %s", got, tt.plain)
+			}
+			if got := e.Alias("x", richAction); got != tt.rich {
+				t.Errorf("rich alias:\n got: %s\nwant: %s", got, tt.rich)
+			}
+		})
+	}
+}
+
+func TestEmitterJump(t *testing.T) {
+	posix := `j() {
+    local dir
+    dir="$(command '/opt/my gtm/gtm' '--config' '/c/it'\''s.toml' 'jump' '--' "$@")" && cd -- "$dir"
+}`
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{"bash", posix},
+		{"zsh", posix},
+		{"fish", `function j --description 'Jump to a workspace'
+    set -l dir (command '/opt/my gtm/gtm' '--config' '/c/it\'s.toml' 'jump' '--' $argv); and cd $dir
+end`},
+		{"nushell", `def --env j [...query: string] {
+    cd (^"/opt/my gtm/gtm" "--config" "/c/it's.toml" "jump" "--" ...$query | str trim)
+}`},
+		{"powershell", `function j {
+    $dir = & '/opt/my gtm/gtm' '--config' '/c/it''s.toml' 'jump' '--' @args
+    if ($LASTEXITCODE -eq 0 -and $dir) { Set-Location -LiteralPath $dir }
+}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			e, err := Lookup(tt.shell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := e.Jump("j", jumpCommand); got != tt.want {
+				t.Errorf("jump:\n got: %s\nwant: %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestBashExecution runs the generated bash code and checks that it ends up
+// in a directory whose name needs every kind of quoting.
+func TestBashExecution(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is not installed")
+	}
+	e, err := Lookup("bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(t.TempDir(), `it's "a" $b\c d ‘q’`)
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	want, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		define string
+		call   string
+		want   string
+	}{
+		{
+			name:   "plain alias",
+			define: e.Alias("x", Action{Dir: dir}),
+			call:   "x && pwd -P",
+			want:   want,
+		},
+		{
+			name: "rich alias",
+			define: e.Alias("x", Action{
+				Dir:  dir,
+				Env:  map[string]string{"NAME": `it's "x" $y`},
+				Run:  "echo ran",
+				Hook: []string{"true", dir},
+			}),
+			call: `x && pwd -P && printf '%s' "$NAME"`,
+			want: "ran\n" + want + "\n" + `it's "x" $y`,
+		},
+		{
+			name:   "jump",
+			define: e.Jump("j", []string{"printf", "%s", dir}),
+			call:   "j && pwd -P",
+			want:   want,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Aliases are only expanded in lines read after their definition
+			script := "shopt -s expand_aliases\n" + tt.define + "\ncd /\n" + tt.call + "\n"
+			out, err := exec.Command(bash, "--noprofile", "--norc", "-c", script).Output()
+			if err != nil {
+				t.Fatalf("bash failed: %v\nscript:\n%s", err, script)
+			}
+			if got := strings.TrimSuffix(string(out), "\n"); got != tt.want {
+				t.Errorf("got %q, want %q\nscript:\n%s", got, tt.want, script)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package shell
+
+import (
+	"fmt"
+	"strings"
+)
+
+func quoteAll(args []string, quote func(string) string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = quote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func (posixEmitter) Jump(name string, command []string) string {
+	return fmt.Sprintf("%s() {\n    local dir\n    dir=\"$(command %s \"$@\")\" && cd -- \"$dir\"\n}",
+		name, quoteAll(command, QuotePOSIX))
+}
+
+func (fishEmitter) Jump(name string, command []string) string {
+	return fmt.Sprintf("function %s --description 'Jump to a workspace'\n    set -l dir (command %s $argv); and cd $dir\nend",
+		name, quoteAll(command, QuoteFish))
+}
+
+func (nuEmitter) Jump(name string, command []string) string {
+	return fmt.Sprintf("def --env %s [...query: string] {\n    cd (^%s ...$query | str trim)\n}",
+		name, quoteAll(command, QuoteNu))
+}
+
+func (powerShellEmitter) Jump(name string, command []string) string {
+	return fmt.Sprintf("function %s {\n    $dir = & %s @args\n    if ($LASTEXITCODE -eq 0 -and $dir) { Set-Location -LiteralPath $dir }\n}",
+		name, quoteAll(command, QuotePowerShell))
+}
@@ -0,0 +1,33 @@
+package shell
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SafeAliasName derives an alias name from a directory name that every
+// supported shell accepts: lowercase ASCII letters, digits, '_' and '-',
+// starting with a letter or '_'. Runs of other characters become a single
+// '-'. It returns "" if nothing usable remains.
+func SafeAliasName(name string) string {
+	var b strings.Builder
+	pendingDash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'):
+			if pendingDash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			pendingDash = false
+			b.WriteRune(r)
+		default:
+			pendingDash = true
+		}
+	}
+
+	safe := b.String()
+	if safe != "" && unicode.IsDigit(rune(safe[0])) {
+		safe = "_" + safe
+	}
+	return safe
+}
@@ -0,0 +1,45 @@
+package shell
+
+import "strings"
+
+// QuotePOSIX quotes s for sh, bash, and zsh. Everything inside single
+// quotes is literal, so an embedded single quote closes the quoted string,
+// adds an escaped quote, and reopens it.
+func QuotePOSIX(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// QuoteFish quotes s for fish. Inside single quotes fish only interprets
+// \' and \\, so both are escaped.
+func QuoteFish(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+// QuoteNu quotes s for nushell as a double-quoted string, escaping the
+// characters nushell interprets inside one.
+func QuoteNu(s string) string {
+	return `"` + strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		"\n", `\n`,
+		"\r", `\r`,
+		"\t", `\t`,
+	).Replace(s) + `"`
+}
+
+// QuotePowerShell quotes s as a PowerShell verbatim string. PowerShell also
+// treats the typographic quotes ‘ ’ ‚ ‛ as single quotes, so every one of
+// them is doubled like '.
+func QuotePowerShell(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\'', '‘', '’', '‚', '‛':
+			b.WriteRune(r)
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
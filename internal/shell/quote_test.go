@@ -0,0 +1,105 @@
+package shell
+
+import (
+	"fmt"
+	"os/exec"
+	"testing"
+)
+
+// trickyPaths contain every character the quoting functions must neutralize.
+var trickyPaths = []string{
+	"/home/me/plain",
+	"/home/me/with space",
+	"/home/me/it's",
+	`/home/me/say "hi"`,
+	`/home/me/back\slash`,
+	"/home/me/$HOME and $(id) and `id`",
+	`/home/me/it's "all" $x\y z`,
+	"/home/me/‘smart’ ‚quotes‛",
+}
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		name  string
+		quote func(string) string
+		in    string
+		want  string
+	}{
+		{"posix plain", QuotePOSIX, "/a/b", `'/a/b'`},
+		{"posix space", QuotePOSIX, "/a/b c", `'/a/b c'`},
+		{"posix single quote", QuotePOSIX, "/a/it's", `'/a/it'\''s'`},
+		{"posix double quote", QuotePOSIX, `/a/"b"`, `'/a/"b"'`},
+		{"posix backslash", QuotePOSIX, `/a/b\c`, `'/a/b\c'`},
+		{"posix dollar", QuotePOSIX, "/a/$b", `'/a/$b'`},
+		{"posix empty", QuotePOSIX, "", `''`},
+
+		{"fish plain", QuoteFish, "/a/b", `'/a/b'`},
+		{"fish space", QuoteFish, "/a/b c", `'/a/b c'`},
+		{"fish single quote", QuoteFish, "/a/it's", `'/a/it\'s'`},
+		{"fish double quote", QuoteFish, `/a/"b"`, `'/a/"b"'`},
+		{"fish backslash", QuoteFish, `/a/b\c`, `'/a/b\\c'`},
+		{"fish backslash before quote", QuoteFish, `/a/b\'c`, `'/a/b\\\'c'`},
+		{"fish dollar", QuoteFish, "/a/$b", `'/a/$b'`},
+
+		{"nu plain", QuoteNu, "/a/b", `"/a/b"`},
+		{"nu space", QuoteNu, "/a/b c", `"/a/b c"`},
+		{"nu single quote", QuoteNu, "/a/it's", `"/a/it's"`},
+		{"nu double quote", QuoteNu, `/a/"b"`, `"/a/\"b\""`},
+		{"nu backslash", QuoteNu, `/a/b\c`, `"/a/b\\c"`},
+		{"nu dollar", QuoteNu, "/a/$b", `"/a/$b"`},
+		{"nu control characters", QuoteNu, "a\tb\nc\rd", `"a\tb\nc\rd"`},
+
+		{"powershell plain", QuotePowerShell, "/a/b", `'/a/b'`},
+		{"powershell space", QuotePowerShell, "/a/b c", `'/a/b c'`},
+		{"powershell single quote", QuotePowerShell, "/a/it's", `'/a/it''s'`},
+		{"powershell double quote", QuotePowerShell, `/a/"b"`, `'/a/"b"'`},
+		{"powershell backslash", QuotePowerShell, `/a/b\c`, `'/a/b\c'`},
+		{"powershell dollar", QuotePowerShell, "/a/$b", `'/a/$b'`},
+		{"powershell smart quotes", QuotePowerShell, "‘a’‚b‛", "'‘‘a’’‚‚b‛‛'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.quote(tt.in); got != tt.want {
+				t.Errorf("quote(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestQuoteRoundTrip has each installed shell print a quoted string back and
+// checks that it comes out unchanged.
+func TestQuoteRoundTrip(t *testing.T) {
+	shells := []struct {
+		binary string
+		quote  func(string) string
+		args   func(script string) []string
+		script string
+	}{
+		{"bash", QuotePOSIX, func(s string) []string { return []string{"-c", s} }, "printf '%%s' %s"},
+		{"zsh", QuotePOSIX, func(s string) []string { return []string{"-f", "-c", s} }, "printf '%%s' %s"},
+		{"fish", QuoteFish, func(s string) []string { return []string{"--no-config", "-c", s} }, "printf '%%s' %s"},
+		{"nu", QuoteNu, func(s string) []string { return []string{"--no-config-file", "-c", s} }, "print -n %s"},
+		{"pwsh", QuotePowerShell, func(s string) []string { return []string{"-NoProfile", "-Command", s} }, "[Console]::Out.Write(%s)"},
+	}
+
+	for _, sh := range shells {
+		t.Run(sh.binary, func(t *testing.T) {
+			binary, err := exec.LookPath(sh.binary)
+			if err != nil {
+				t.Skipf("%s is not installed", sh.binary)
+			}
+			for _, path := range trickyPaths {
+				script := fmt.Sprintf(sh.script, sh.quote(path))
+				out, err := exec.Command(binary, sh.args(script)...).Output()
+				if err != nil {
+					t.Errorf("%s -c %q: %v", sh.binary, script, err)
+					continue
+				}
+				if string(out) != path {
+					t.Errorf("%s printed %q for %q", sh.binary, out, path)
+				}
+			}
+		})
+	}
+}
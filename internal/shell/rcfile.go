@@ -0,0 +1,86 @@
+package shell
+
+import "strings"
+
+// Markers delimit the block GoTagManager manages inside a shell rc file.
+// Every supported shell uses '#' for comments.
+const (
+	BlockStart = "# >>> GoTagManager aliases >>>"
+	BlockEnd   = "# <<< GoTagManager aliases <<<"
+)
+
+// ReplaceBlock returns content with the managed block set to body. An
+// existing block is replaced in place; otherwise the block is appended.
+// Everything outside the markers is left untouched.
+func ReplaceBlock(content, body string) string {
+	block := BlockStart + "\n" +
+		"# Managed by GoTagManager; changes inside this block are overwritten.\n" +
+		strings.TrimRight(body, "\n") + "\n" +
+		BlockEnd + "\n"
+
+	if start, end, ok := findBlock(content); ok {
+		return content[:start] + block + content[end:]
+	}
+
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if content != "" {
+		content += "\n"
+	}
+	return content + block
+}
+
+// RemoveBlock returns content without the managed block and reports whether
+// a block was found.
+func RemoveBlock(content string) (string, bool) {
+	start, end, ok := findBlock(content)
+	if !ok {
+		return content, false
+	}
+
+	before := content[:start]
+	// Drop the blank line ReplaceBlock put in front of an appended block
+	if strings.HasSuffix(before, "\n\n") {
+		before = before[:len(before)-1]
+	}
+	return before + content[end:], true
+}
+
+// findBlock locates the managed block, returning the offset of the start
+// marker and the offset just past the end marker's line.
+func findBlock(content string) (int, int, bool) {
+	start := indexLine(content, BlockStart, 0)
+	if start < 0 {
+		return 0, 0, false
+	}
+	end := indexLine(content, BlockEnd, start)
+	if end < 0 {
+		return 0, 0, false
+	}
+	end += len(BlockEnd)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return start, end, true
+}
+
+// indexLine returns the offset of the first line at or after from that
+// consists of exactly line, or -1.
+func indexLine(content, line string, from int) int {
+	for offset := from; offset <= len(content); {
+		i := strings.Index(content[offset:], line)
+		if i < 0 {
+			return -1
+		}
+		i += offset
+		atLineStart := i == 0 || content[i-1] == '\n'
+		after := i + len(line)
+		atLineEnd := after == len(content) || content[after] == '\n' || content[after] == '\r'
+		if atLineStart && atLineEnd {
+			return i
+		}
+		offset = i + 1
+	}
+	return -1
+}
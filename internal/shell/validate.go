@@ -0,0 +1,115 @@
+package shell
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+)
+
+// aliasNamePattern is the set of alias names every supported shell can
+// define: a letter or '_' followed by letters, digits, '_' or '-'. The '-'
+// goes beyond shell identifiers on purpose: all supported shells accept it in
+// alias and function names, and the suffix collision policy relies on it.
+var aliasNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// AliasError explains why an alias name was rejected.
+type AliasError struct {
+	Alias  string
+	Reason string
+}
+
+func (e *AliasError) Error() string {
+	return fmt.Sprintf("alias '%s' %s", e.Alias, e.Reason)
+}
+
+// ValidateAliasSyntax checks that name matches aliasNamePattern.
+func ValidateAliasSyntax(name string) error {
+	if !aliasNamePattern.MatchString(name) {
+		return &AliasError{Alias: name, Reason: "is not a valid alias name (it must start with a letter or '_' and contain only letters, digits, '_' and '-')"}
+	}
+	return nil
+}
+
+// ValidateAliasName checks that name is a valid alias name and does not
+// shadow a builtin or keyword of the emitter's shell. When checkPath is true
+// it also rejects names of executables found on $PATH.
+func ValidateAliasName(name string, e Emitter, checkPath bool) error {
+	if err := ValidateAliasSyntax(name); err != nil {
+		return err
+	}
+
+	for _, reserved := range e.Reserved() {
+		if name == reserved {
+			return &AliasError{Alias: name, Reason: fmt.Sprintf("shadows the %s builtin or keyword '%s'", e.Name(), reserved)}
+		}
+	}
+
+	if checkPath {
+		if path, err := exec.LookPath(name); err == nil {
+			return &AliasError{Alias: name, Reason: fmt.Sprintf("shadows the executable %s on $PATH", path)}
+		}
+	}
+	return nil
+}
+
+// posixReserved are the builtins and reserved words that bash and zsh share.
+var posixReserved = []string{
+	"alias", "bg", "break", "builtin", "case", "cd", "command", "continue",
+	"coproc", "declare", "dirs", "disown", "do", "done", "echo", "elif", "else",
+	"enable", "esac", "eval", "exec", "exit", "export", "false", "fc", "fg",
+	"fi", "for", "function", "getopts", "hash", "history", "if", "in", "jobs",
+	"kill", "let", "local", "logout", "popd", "printf", "pushd", "pwd", "read",
+	"readonly", "return", "select", "set", "shift", "source", "suspend", "test",
+	"then", "time", "times", "trap", "true", "type", "typeset", "ulimit",
+	"umask", "unalias", "unset", "until", "wait", "while",
+}
+
+// bashReserved extends posixReserved with bash-only builtins, so that it
+// holds every name listed by "compgen -b -k".
+var bashReserved = append([]string{
+	"bind", "caller", "compgen", "complete", "compopt", "help", "mapfile",
+	"readarray", "shopt",
+}, posixReserved...)
+
+// zshReserved extends posixReserved with zsh-only builtins and reserved
+// words.
+var zshReserved = append([]string{
+	"autoload", "bindkey", "bye", "chdir", "compdef", "disable", "emulate",
+	"float", "foreach", "functions", "integer", "limit", "log", "noglob",
+	"nocorrect", "print", "private", "pushln", "r", "rehash", "repeat", "sched",
+	"setopt", "unfunction", "unhash", "unlimit", "unsetopt", "vared", "whence",
+	"where", "which", "zcompile", "zformat", "zle", "zmodload", "zparseopts",
+	"zstyle",
+}, posixReserved...)
+
+// fishReserved are fish builtins and keywords.
+var fishReserved = []string{
+	"abbr", "and", "argparse", "begin", "bg", "bind", "block", "break",
+	"builtin", "case", "cd", "command", "commandline", "complete", "contains",
+	"continue", "count", "echo", "else", "emit", "end", "eval", "exec", "exit",
+	"false", "fg", "for", "function", "functions", "history", "if", "jobs",
+	"math", "not", "or", "printf", "pwd", "random", "read", "realpath",
+	"return", "set", "source", "status", "string", "switch", "test", "time",
+	"true", "type", "ulimit", "wait", "while",
+}
+
+// nuReserved are nushell keywords and core commands.
+var nuReserved = []string{
+	"alias", "all", "any", "append", "break", "cd", "continue", "def", "do",
+	"each", "echo", "else", "error", "exit", "export", "extern", "filter",
+	"first", "for", "get", "hide", "if", "last", "length", "let", "lines",
+	"loop", "ls", "match", "mut", "module", "open", "overlay", "print",
+	"reduce", "return", "save", "select", "sort-by", "source", "try", "use",
+	"where", "while", "with-env",
+}
+
+// powerShellReserved are PowerShell keywords and default aliases.
+var powerShellReserved = []string{
+	"begin", "break", "cat", "catch", "cd", "class", "clear", "cls", "continue",
+	"copy", "cp", "data", "define", "del", "dir", "do", "dynamicparam", "echo",
+	"else", "elseif", "end", "enum", "exit", "filter", "finally", "for",
+	"foreach", "from", "function", "gci", "gl", "hidden", "if", "in", "kill",
+	"ls", "md", "mv", "param", "popd", "process", "ps", "pushd", "pwd", "r",
+	"rm", "rmdir", "return", "sl", "static", "switch", "throw", "trap", "try",
+	"type", "until", "using", "var", "where", "while",
+}
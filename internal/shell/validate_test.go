@@ -0,0 +1,116 @@
+package shell
+
+import (
+	"os/exec"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestValidateAliasSyntax(t *testing.T) {
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{"proj", true},
+		{"_proj", true},
+		{"Proj2", true},
+		{"my_proj", true},
+		// '-' is accepted by every supported shell, so it is allowed even
+		// though it is not part of a shell identifier.
+		{"my-proj", true},
+		{"dup-alpha-2", true},
+		{"", false},
+		{"2proj", false},
+		{"-proj", false},
+		{"my proj", false},
+		{"my.proj", false},
+		{"my/proj", false},
+		{"proj$", false},
+		{"prój", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAliasSyntax(tt.name)
+			if tt.valid && err != nil {
+				t.Errorf("ValidateAliasSyntax(%q) = %v, want nil", tt.name, err)
+			}
+			if !tt.valid {
+				if err == nil {
+					t.Fatalf("ValidateAliasSyntax(%q) = nil, want an error", tt.name)
+				}
+				if !strings.Contains(err.Error(), "'-'") {
+					t.Errorf("error %q does not say that '-' is allowed", err)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAliasNameReserved(t *testing.T) {
+	tests := []struct {
+		shell  string
+		alias  string
+		reject bool
+	}{
+		{"bash", "cd", true},
+		{"bash", "caller", true},
+		{"bash", "mapfile", true},
+		{"bash", "readarray", true},
+		{"bash", "coproc", true},
+		{"bash", "suspend", true},
+		{"bash", "compgen", true},
+		{"bash", "complete", true},
+		{"bash", "bind", true},
+		{"bash", "setopt", false},
+		{"zsh", "setopt", true},
+		{"zsh", "coproc", true},
+		{"zsh", "vared", true},
+		{"zsh", "mapfile", false},
+		{"fish", "abbr", true},
+		{"nushell", "sort-by", true},
+		{"powershell", "foreach", true},
+		{"bash", "proj", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell+" "+tt.alias, func(t *testing.T) {
+			e, err := Lookup(tt.shell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = ValidateAliasName(tt.alias, e, false)
+			if tt.reject && err == nil {
+				t.Errorf("ValidateAliasName(%q) for %s = nil, want an error", tt.alias, tt.shell)
+			}
+			if !tt.reject && err != nil {
+				t.Errorf("ValidateAliasName(%q) for %s = %v, want nil", tt.alias, tt.shell, err)
+			}
+		})
+	}
+}
+
+// TestBashReservedComplete checks bashReserved against the builtins and
+// keywords of the installed bash.
+func TestBashReservedComplete(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is not installed")
+	}
+	out, err := exec.Command(bash, "--noprofile", "--norc", "-c", "compgen -b; compgen -k").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reserved := make(map[string]bool, len(bashReserved))
+	for _, name := range bashReserved {
+		reserved[name] = true
+	}
+	identifier := regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	for _, name := range strings.Fields(string(out)) {
+		if identifier.MatchString(name) && !reserved[name] {
+			t.Errorf("bash builtin or keyword %q is missing from bashReserved", name)
+		}
+	}
+}
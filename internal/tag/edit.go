@@ -0,0 +1,166 @@
+package tag
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// Normalize canonicalizes a single tag: surrounding whitespace is trimmed,
+// letters are lowercased, and inner whitespace runs become a single '-'.
+// Each level of a hierarchical tag is normalized separately and empty
+// levels are dropped, so " Client / ACME/ " becomes "client/acme".
+func Normalize(tag string) string {
+	var segments []string
+	for _, segment := range strings.Split(tag, Separator) {
+		segment = strings.Join(strings.Fields(strings.ToLower(segment)), "-")
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return strings.Join(segments, Separator)
+}
+
+// NormalizeAll normalizes every tag, dropping empty tags and duplicates while
+// preserving the original order.
+func NormalizeAll(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		n := Normalize(t)
+		if n == "" || seen[n] {
+			continue
+		}
+		seen[n] = true
+		result = append(result, n)
+	}
+	return result
+}
+
+// AddTags returns existing with tags appended, normalized and deduplicated.
+func AddTags(existing, tags []string) []string {
+	return NormalizeAll(append(append([]string{}, existing...), tags...))
+}
+
+// RemoveTags returns existing without any tag matching one of the given
+// tags. Wildcard patterns such as "lang:*" are allowed.
+func RemoveTags(existing, tags []string) []string {
+	result := make([]string, 0, len(existing))
+	for _, t := range NormalizeAll(existing) {
+		remove := false
+		for _, pattern := range tags {
+			if MatchPattern(Normalize(pattern), t) {
+				remove = true
+				break
+			}
+		}
+		if !remove {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// SetTags returns tags normalized and deduplicated, discarding existing.
+func SetTags(existing, tags []string) []string {
+	return NormalizeAll(tags)
+}
+
+// EqualTags reports whether a and b contain the same tags in the same order.
+func EqualTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ReplaceTags returns existing with every tag in from replaced by to. Tags
+// nested below a replaced tag move along with it, so replacing "client"
+// with "customer" turns "client/acme" into "customer/acme". The replacement
+// keeps the position of the first replaced tag, and the result is
+// normalized and deduplicated.
+func ReplaceTags(existing, from []string, to string) []string {
+	result := make([]string, 0, len(existing))
+	for _, t := range NormalizeAll(existing) {
+		replaced := t
+		for _, f := range from {
+			f = Normalize(f)
+			if IsWithin(t, f) {
+				replaced = to + t[len(f):]
+				break
+			}
+		}
+		result = append(result, replaced)
+	}
+	return NormalizeAll(result)
+}
+
+// TagUpdate records the tags of a workspace before and after an edit.
+type TagUpdate struct {
+	Workspace workspace.Workspace
+	Before    []string
+	After     []string
+
+	info *workspace.WorkspaceInfo
+}
+
+// Changed reports whether the update modifies the workspace's tags.
+func (u TagUpdate) Changed() bool {
+	return !EqualTags(u.Before, u.After)
+}
+
+// PlanTagUpdates applies edit to the tags of every workspace in workspaces
+// without writing anything. It fails if any ws_info.toml cannot be parsed,
+// so callers never act on a partial view of the root.
+func PlanTagUpdates(workspaces []workspace.Workspace, edit func([]string) []string) ([]TagUpdate, error) {
+	updates := make([]TagUpdate, 0, len(workspaces))
+	for _, ws := range workspaces {
+		wsInfoPath := filepath.Join(ws.Path, "ws_info.toml")
+		info, err := workspace.ParseWSInfo(wsInfoPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", wsInfoPath, err)
+		}
+
+		updates = append(updates, TagUpdate{
+			Workspace: ws,
+			Before:    info.Info.Tags,
+			After:     edit(info.Info.Tags),
+			info:      info,
+		})
+	}
+	return updates, nil
+}
+
+// ApplyTagUpdates writes every changed update back to its ws_info.toml.
+func ApplyTagUpdates(updates []TagUpdate) error {
+	for _, update := range updates {
+		if !update.Changed() {
+			continue
+		}
+		update.info.Info.Tags = update.After
+		wsInfoPath := filepath.Join(update.Workspace.Path, "ws_info.toml")
+		if err := workspace.SaveWSInfo(wsInfoPath, update.info); err != nil {
+			return fmt.Errorf("failed to write %s: %w", wsInfoPath, err)
+		}
+	}
+	return nil
+}
+
+// UpdateTags applies edit to the tags of every workspace in workspaces.
+// All ws_info.toml files are parsed before any is written, so a parse error
+// in one workspace leaves every workspace untouched. Files whose tags do not
+// change are not rewritten.
+func UpdateTags(workspaces []workspace.Workspace, edit func([]string) []string) ([]TagUpdate, error) {
+	updates, err := PlanTagUpdates(workspaces, edit)
+	if err != nil {
+		return nil, err
+	}
+	return updates, ApplyTagUpdates(updates)
+}
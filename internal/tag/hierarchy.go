@@ -0,0 +1,131 @@
+package tag
+
+import (
+	"sort"
+	"strings"
+)
+
+// Separator splits a hierarchical tag such as "client/acme" into levels.
+const Separator = "/"
+
+// Parent returns the parent of a hierarchical tag, or "" for a top-level tag.
+func Parent(tag string) string {
+	i := strings.LastIndex(tag, Separator)
+	if i < 0 {
+		return ""
+	}
+	return tag[:i]
+}
+
+// Ancestors returns tag and each of its parents, from the most specific
+// ("client/acme/site") to the top level ("client").
+func Ancestors(tag string) []string {
+	ancestors := []string{tag}
+	for p := Parent(tag); p != ""; p = Parent(p) {
+		ancestors = append(ancestors, p)
+	}
+	return ancestors
+}
+
+// IsWithin reports whether tag equals parent or is nested below it,
+// so "client/acme" is within "client" but "clients" is not.
+func IsWithin(tag, parent string) bool {
+	return tag == parent || strings.HasPrefix(tag, parent+Separator)
+}
+
+// CompleteLevel completes word one hierarchy level at a time against the
+// known tags. For the word "cl" and the tags "client/acme" and "client/beta"
+// it returns "client/"; for "client/" it returns "client/acme" and
+// "client/beta". A tag that is both used on its own and has children is
+// offered in both forms.
+func CompleteLevel(tags []string, word string) []string {
+	base := ""
+	if i := strings.LastIndex(word, Separator); i >= 0 {
+		base = word[:i+len(Separator)]
+	}
+
+	seen := make(map[string]bool)
+	var completions []string
+	add := func(s string) {
+		if !seen[s] {
+			seen[s] = true
+			completions = append(completions, s)
+		}
+	}
+
+	for _, t := range tags {
+		if !strings.HasPrefix(t, word) {
+			continue
+		}
+		rest := t[len(base):]
+		if i := strings.Index(rest, Separator); i >= 0 {
+			add(base + rest[:i+len(Separator)])
+		} else {
+			add(t)
+		}
+	}
+
+	sort.Strings(completions)
+	return completions
+}
+
+// TreeNode is one level of the tag hierarchy. Stat aggregates every
+// workspace tagged with the node's tag or any tag nested below it.
+type TreeNode struct {
+	Segment  string
+	Stat     Stat
+	Children []*TreeNode
+}
+
+// BuildTree arranges per-tag statistics into a hierarchy. sizes maps
+// workspace names to disk sizes so that a workspace counted at several
+// levels of one branch contributes its size only once per node.
+func BuildTree(stats []Stat, sizes map[string]int64) []*TreeNode {
+	root := &TreeNode{}
+	members := make(map[*TreeNode]map[string]bool)
+
+	for _, stat := range stats {
+		node := root
+		path := ""
+		for _, segment := range strings.Split(stat.Tag, Separator) {
+			if path == "" {
+				path = segment
+			} else {
+				path += Separator + segment
+			}
+
+			var child *TreeNode
+			for _, c := range node.Children {
+				if c.Segment == segment {
+					child = c
+					break
+				}
+			}
+			if child == nil {
+				child = &TreeNode{Segment: segment, Stat: Stat{Tag: path}}
+				node.Children = append(node.Children, child)
+				members[child] = make(map[string]bool)
+			}
+
+			for _, ws := range stat.Workspaces {
+				if !members[child][ws] {
+					members[child][ws] = true
+					child.Stat.Workspaces = append(child.Stat.Workspaces, ws)
+					child.Stat.Size += sizes[ws]
+				}
+			}
+			node = child
+		}
+	}
+
+	sortTree(root.Children)
+	return root.Children
+}
+
+func sortTree(nodes []*TreeNode) {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Segment < nodes[j].Segment })
+	for _, n := range nodes {
+		sort.Strings(n.Stat.Workspaces)
+		sortTree(n.Children)
+	}
+}
@@ -0,0 +1,127 @@
+package tag
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/johnjallday/GoTagManager/config"
+	"github.com/johnjallday/GoTagManager/internal/workspace"
+)
+
+// Expand returns tags plus every tag implied by rules, applied repeatedly
+// until no rule adds anything new. A rule applies when its pattern matches
+// a tag or one of its parents, the same way a query term does. Expand always
+// terminates, even if the rules contain cycles.
+func Expand(tags []string, rules []config.TagImplication) []string {
+	effective := NormalizeAll(tags)
+	seen := make(map[string]bool, len(effective))
+	for _, t := range effective {
+		seen[t] = true
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, rule := range rules {
+			if !(termQuery{pattern: Normalize(rule.If)}).Match(effective) {
+				continue
+			}
+			for _, implied := range rule.Then {
+				n := Normalize(implied)
+				if n != "" && !seen[n] {
+					seen[n] = true
+					effective = append(effective, n)
+					changed = true
+				}
+			}
+		}
+	}
+	return effective
+}
+
+// Load parses the ws_info.toml of a workspace and computes its EffectiveTags
+// from the implication rules.
+func Load(workspacePath string, rules []config.TagImplication) (*workspace.WorkspaceInfo, error) {
+	info, err := workspace.ParseWSInfo(filepath.Join(workspacePath, "ws_info.toml"))
+	if err != nil {
+		return nil, err
+	}
+	info.EffectiveTags = Expand(info.Info.Tags, rules)
+	return info, nil
+}
+
+// FindCycles returns every cycle in the implication rules. Each cycle is the
+// list of rule patterns along it, starting and ending with the same pattern,
+// e.g. ["a", "b", "a"]. Cycles do not break expansion, but they usually mean
+// a rule was written the wrong way round.
+func FindCycles(rules []config.TagImplication) [][]string {
+	// Rule i has an edge to rule j when one of the tags i implies would
+	// trigger j.
+	edges := make([][]int, len(rules))
+	for i, from := range rules {
+		for j, to := range rules {
+			pattern := termQuery{pattern: Normalize(to.If)}
+			for _, implied := range from.Then {
+				if pattern.Match([]string{Normalize(implied)}) {
+					edges[i] = append(edges[i], j)
+					break
+				}
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make([]int, len(rules))
+	var stack []int
+	var cycles [][]string
+	seen := make(map[string]bool)
+
+	var visit func(i int)
+	visit = func(i int) {
+		state[i] = inProgress
+		stack = append(stack, i)
+		for _, j := range edges[i] {
+			switch state[j] {
+			case unvisited:
+				visit(j)
+			case inProgress:
+				// Back edge: the stack from j to i is a cycle.
+				start := len(stack) - 1
+				for stack[start] != j {
+					start--
+				}
+				cycle := make([]string, 0, len(stack)-start+1)
+				for _, k := range stack[start:] {
+					cycle = append(cycle, Normalize(rules[k].If))
+				}
+				cycle = append(cycle, cycle[0])
+
+				key := cycleKey(cycle[:len(cycle)-1])
+				if !seen[key] {
+					seen[key] = true
+					cycles = append(cycles, cycle)
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = done
+	}
+
+	for i := range rules {
+		if state[i] == unvisited {
+			visit(i)
+		}
+	}
+	return cycles
+}
+
+// cycleKey identifies a cycle independently of where it was entered.
+func cycleKey(nodes []string) string {
+	sorted := append([]string{}, nodes...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, "\x00")
+}
@@ -0,0 +1,42 @@
+package tag
+
+import "strings"
+
+// MatchPattern reports whether tag matches pattern. The pattern may contain
+// '*' (any run of characters) and '?' (any single character). Matching is
+// case-insensitive.
+func MatchPattern(pattern, tag string) bool {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(tag))
+
+	// Iterative glob matching with single-star backtracking.
+	pi, ti := 0, 0
+	starIdx, matchIdx := -1, 0
+	for ti < len(t) {
+		switch {
+		case pi < len(p) && (p[pi] == '?' || p[pi] == t[ti]):
+			pi++
+			ti++
+		case pi < len(p) && p[pi] == '*':
+			starIdx = pi
+			matchIdx = ti
+			pi++
+		case starIdx != -1:
+			pi = starIdx + 1
+			matchIdx++
+			ti = matchIdx
+		default:
+			return false
+		}
+	}
+
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// IsPattern reports whether s contains wildcard characters.
+func IsPattern(s string) bool {
+	return strings.ContainsAny(s, "*?")
+}
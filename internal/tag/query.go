@@ -0,0 +1,232 @@
+package tag
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Query is a parsed boolean tag expression such as
+// "go and client and not archived" or "(lang/* or docker) !legacy".
+// A term also matches the tags nested below it in the tag hierarchy.
+type Query interface {
+	// Match reports whether the given set of tags satisfies the query.
+	Match(tags []string) bool
+	String() string
+}
+
+type termQuery struct{ pattern string }
+
+// Match reports whether any tag, or any parent of a hierarchical tag,
+// matches the pattern; "client" therefore matches "client/acme".
+func (q termQuery) Match(tags []string) bool {
+	for _, t := range tags {
+		for _, a := range Ancestors(t) {
+			if MatchPattern(q.pattern, a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (q termQuery) String() string { return q.pattern }
+
+type notQuery struct{ operand Query }
+
+func (q notQuery) Match(tags []string) bool { return !q.operand.Match(tags) }
+func (q notQuery) String() string           { return "NOT " + q.operand.String() }
+
+type andQuery struct{ left, right Query }
+
+func (q andQuery) Match(tags []string) bool { return q.left.Match(tags) && q.right.Match(tags) }
+func (q andQuery) String() string {
+	return "(" + q.left.String() + " AND " + q.right.String() + ")"
+}
+
+type orQuery struct{ left, right Query }
+
+func (q orQuery) Match(tags []string) bool { return q.left.Match(tags) || q.right.Match(tags) }
+func (q orQuery) String() string {
+	return "(" + q.left.String() + " OR " + q.right.String() + ")"
+}
+
+type tokenKind int
+
+const (
+	tokTerm tokenKind = iota
+	tokAnd
+	tokOr
+	tokNot
+	tokLParen
+	tokRParen
+	tokEOF
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// ParseQuery parses a boolean tag expression.
+//
+// Supported operators are "and" / "&&", "or" / "||", "not" / "!" and
+// parentheses. Adjacent terms are joined with an implicit "and". Terms may
+// use '*' and '?' wildcards, e.g. "lang:*" or "client/*".
+func ParseQuery(expr string) (Query, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryParser{tokens: tokens}
+	if p.peek().kind == tokEOF {
+		return nil, fmt.Errorf("empty tag expression")
+	}
+
+	q, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q in tag expression", tok.text)
+	}
+	return q, nil
+}
+
+// tokenize splits an expression into operator and term tokens.
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokLParen, "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokRParen, ")"})
+			i++
+		case r == '!':
+			tokens = append(tokens, token{tokNot, "!"})
+			i++
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("unexpected %q in tag expression; use %q", string(r), string([]rune{r, r}))
+			}
+			if r == '&' {
+				tokens = append(tokens, token{tokAnd, "&&"})
+			} else {
+				tokens = append(tokens, token{tokOr, "||"})
+			}
+			i += 2
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune("()!&|", runes[i]) {
+				i++
+			}
+			word := string(runes[start:i])
+			switch strings.ToLower(word) {
+			case "and":
+				tokens = append(tokens, token{tokAnd, word})
+			case "or":
+				tokens = append(tokens, token{tokOr, word})
+			case "not":
+				tokens = append(tokens, token{tokNot, word})
+			default:
+				tokens = append(tokens, token{tokTerm, word})
+			}
+		}
+	}
+
+	return append(tokens, token{tokEOF, "end of expression"}), nil
+}
+
+// queryParser is a recursive-descent parser over the token stream.
+// Precedence from lowest to highest: or, and, not.
+type queryParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *queryParser) peek() token { return p.tokens[p.pos] }
+
+func (p *queryParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryParser) parseOr() (Query, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orQuery{left, right}
+	}
+	return left, nil
+}
+
+func (p *queryParser) parseAnd() (Query, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokAnd:
+			p.next()
+		case tokTerm, tokNot, tokLParen:
+			// Implicit "and" between adjacent operands.
+		default:
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = andQuery{left, right}
+	}
+}
+
+func (p *queryParser) parseNot() (Query, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notQuery{operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (Query, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokTerm:
+		return termQuery{pattern: tok.text}, nil
+	case tokLParen:
+		q, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, fmt.Errorf("expected ')' but found %q in tag expression", closing.text)
+		}
+		return q, nil
+	default:
+		return nil, fmt.Errorf("expected a tag but found %q in tag expression", tok.text)
+	}
+}
@@ -0,0 +1,147 @@
+package tag
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/johnjallday/GoTagManager/config"
+)
+
+// Registry enforces the controlled tag vocabulary from the configuration.
+// A registry with an empty vocabulary accepts every tag.
+type Registry struct {
+	strict      bool
+	definitions map[string]config.TagDefinition
+	canonical   map[string]string // normalized name or synonym -> canonical name
+}
+
+// UnknownTagError is returned for a tag that is not in the vocabulary.
+type UnknownTagError struct {
+	Tag        string
+	Suggestion string
+}
+
+func (e *UnknownTagError) Error() string {
+	if e.Suggestion != "" {
+		return fmt.Sprintf("tag '%s' is not in the tag vocabulary; did you mean '%s'?", e.Tag, e.Suggestion)
+	}
+	return fmt.Sprintf("tag '%s' is not in the tag vocabulary", e.Tag)
+}
+
+// NewRegistry builds a Registry from the [tags] configuration section.
+func NewRegistry(cfg config.TagRegistry) *Registry {
+	r := &Registry{
+		strict:      cfg.Mode == config.TagModeStrict,
+		definitions: make(map[string]config.TagDefinition),
+		canonical:   make(map[string]string),
+	}
+	for _, def := range cfg.Vocabulary {
+		name := Normalize(def.Name)
+		def.Name = name
+		r.definitions[name] = def
+		r.canonical[name] = name
+		for _, synonym := range def.Synonyms {
+			r.canonical[Normalize(synonym)] = name
+		}
+	}
+	return r
+}
+
+// Enabled reports whether a vocabulary is configured.
+func (r *Registry) Enabled() bool {
+	return len(r.definitions) > 0
+}
+
+// Strict reports whether unknown tags are rejected rather than warned about.
+func (r *Registry) Strict() bool {
+	return r.strict
+}
+
+// Definition returns the vocabulary entry for a canonical tag.
+func (r *Registry) Definition(tag string) (config.TagDefinition, bool) {
+	def, ok := r.definitions[Normalize(tag)]
+	return def, ok
+}
+
+// Names returns the canonical tag names in sorted order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.definitions))
+	for name := range r.definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve normalizes tag and maps synonyms to their canonical tag.
+// It returns an *UnknownTagError if the registry is enabled and the tag is
+// not in the vocabulary; the normalized tag is still returned in that case.
+func (r *Registry) Resolve(tag string) (string, error) {
+	n := Normalize(tag)
+	if !r.Enabled() {
+		return n, nil
+	}
+	if canonical, ok := r.canonical[n]; ok {
+		return canonical, nil
+	}
+	return n, &UnknownTagError{Tag: n, Suggestion: r.Suggest(n)}
+}
+
+// ResolveAll resolves every tag. Unknown tags are returned as errors; in
+// strict mode they are dropped from the result, in warn mode they are kept.
+func (r *Registry) ResolveAll(tags []string) ([]string, []error) {
+	var result []string
+	var problems []error
+	for _, t := range tags {
+		resolved, err := r.Resolve(t)
+		if err != nil {
+			problems = append(problems, err)
+			if r.strict {
+				continue
+			}
+		}
+		result = append(result, resolved)
+	}
+	return NormalizeAll(result), problems
+}
+
+// Suggest returns the canonical tag closest to tag, or "" if nothing is
+// reasonably close.
+func (r *Registry) Suggest(tag string) string {
+	n := Normalize(tag)
+	maxDistance := len([]rune(n)) / 3
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
+
+	best, bestDistance := "", maxDistance+1
+	for candidate, canonical := range r.canonical {
+		d := levenshtein(n, candidate)
+		if d < bestDistance || (d == bestDistance && canonical < best) {
+			best, bestDistance = canonical, d
+		}
+	}
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,70 @@
+package tag
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Stat aggregates one tag across a set of workspaces.
+type Stat struct {
+	Tag        string
+	Workspaces []string
+	Size       int64
+}
+
+// Count returns the number of workspaces using the tag.
+func (s Stat) Count() int {
+	return len(s.Workspaces)
+}
+
+// Aggregate builds one Stat per tag from the tags of each workspace.
+// sizes maps workspace names to their disk size and may be nil.
+func Aggregate(tagsByWorkspace map[string][]string, sizes map[string]int64) []Stat {
+	byTag := make(map[string]*Stat)
+	for workspaceName, tags := range tagsByWorkspace {
+		for _, t := range NormalizeAll(tags) {
+			stat, ok := byTag[t]
+			if !ok {
+				stat = &Stat{Tag: t}
+				byTag[t] = stat
+			}
+			stat.Workspaces = append(stat.Workspaces, workspaceName)
+			stat.Size += sizes[workspaceName]
+		}
+	}
+
+	stats := make([]Stat, 0, len(byTag))
+	for _, stat := range byTag {
+		sort.Strings(stat.Workspaces)
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Tag < stats[j].Tag })
+	return stats
+}
+
+// SortStats orders stats by "name" (ascending), "count" or "size"
+// (both descending). Ties are broken by tag name.
+func SortStats(stats []Stat, by string) error {
+	var less func(a, b Stat) bool
+	switch by {
+	case "name":
+		less = func(a, b Stat) bool { return false }
+	case "count":
+		less = func(a, b Stat) bool { return a.Count() > b.Count() }
+	case "size":
+		less = func(a, b Stat) bool { return a.Size > b.Size }
+	default:
+		return fmt.Errorf("unknown sort order '%s' (expected count, name, or size)", by)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		if less(stats[i], stats[j]) {
+			return true
+		}
+		if less(stats[j], stats[i]) {
+			return false
+		}
+		return stats[i].Tag < stats[j].Tag
+	})
+	return nil
+}
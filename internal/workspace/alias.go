@@ -0,0 +1,160 @@
+package workspace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Alias is one entry of [info].aliases. An entry is either a plain name,
+// which cds into the workspace, or an inline table describing what entering
+// the workspace should do:
+//
+//	aliases = [
+//	  "site",
+//	  { name = "api", dir = "services/api", venv = ".venv", env = { DEBUG = "1" }, run = "make dev" },
+//	  { name = "ops", tmux = "ops" },
+//	]
+type Alias struct {
+	Name string            `toml:"name"`
+	Dir  string            `toml:"dir"`  // subdirectory to cd into, relative to the workspace
+	Venv string            `toml:"venv"` // Python virtualenv to activate, relative to Dir
+	Env  map[string]string `toml:"env"`  // environment variables to export
+	Run  string            `toml:"run"`  // command run after entering, in the target shell's syntax
+	Tmux string            `toml:"tmux"` // tmux session to create or attach to
+}
+
+// PlainAliases turns alias names into plain cd aliases.
+func PlainAliases(names []string) []Alias {
+	aliases := make([]Alias, 0, len(names))
+	for _, name := range names {
+		aliases = append(aliases, Alias{Name: name})
+	}
+	return aliases
+}
+
+// AliasNames returns the names of aliases.
+func AliasNames(aliases []Alias) []string {
+	names := make([]string, 0, len(aliases))
+	for _, a := range aliases {
+		names = append(names, a.Name)
+	}
+	return names
+}
+
+// IsPlain reports whether the alias only cds into the workspace.
+func (a Alias) IsPlain() bool {
+	return a.Dir == "" && a.Venv == "" && len(a.Env) == 0 && a.Run == "" && a.Tmux == ""
+}
+
+// String describes the alias and its actions for display.
+func (a Alias) String() string {
+	if a.IsPlain() {
+		return a.Name
+	}
+
+	var actions []string
+	if a.Dir != "" {
+		actions = append(actions, "dir="+a.Dir)
+	}
+	if a.Venv != "" {
+		actions = append(actions, "venv="+a.Venv)
+	}
+	keys := make([]string, 0, len(a.Env))
+	for k := range a.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		actions = append(actions, fmt.Sprintf("env %s=%s", k, a.Env[k]))
+	}
+	if a.Run != "" {
+		actions = append(actions, fmt.Sprintf("run=%q", a.Run))
+	}
+	if a.Tmux != "" {
+		actions = append(actions, "tmux="+a.Tmux)
+	}
+	return fmt.Sprintf("%s (%s)", a.Name, strings.Join(actions, ", "))
+}
+
+// UnmarshalTOML accepts both the plain string form and the table form.
+func (a *Alias) UnmarshalTOML(data interface{}) error {
+	switch v := data.(type) {
+	case string:
+		*a = Alias{Name: v}
+		return nil
+	case map[string]interface{}:
+		*a = Alias{}
+		for key, value := range v {
+			var err error
+			switch key {
+			case "name":
+				a.Name, err = tomlString(key, value)
+			case "dir":
+				a.Dir, err = tomlString(key, value)
+			case "venv":
+				a.Venv, err = tomlString(key, value)
+			case "run":
+				a.Run, err = tomlString(key, value)
+			case "tmux":
+				a.Tmux, err = tomlString(key, value)
+			case "env":
+				table, ok := value.(map[string]interface{})
+				if !ok {
+					return fmt.Errorf("alias key 'env' must be a table")
+				}
+				a.Env = make(map[string]string, len(table))
+				for k, val := range table {
+					if a.Env[k], err = tomlString("env."+k, val); err != nil {
+						return err
+					}
+				}
+			default:
+				return fmt.Errorf("unknown alias key '%s'", key)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if a.Name == "" {
+			return fmt.Errorf("alias table without a name")
+		}
+		return nil
+	default:
+		return fmt.Errorf("alias must be a string or a table, not %T", data)
+	}
+}
+
+// toTOML returns the value SaveWSInfo writes for the alias: a plain string
+// when possible, so files using the old form keep it.
+func (a Alias) toTOML() interface{} {
+	if a.IsPlain() {
+		return a.Name
+	}
+
+	table := map[string]interface{}{"name": a.Name}
+	if a.Dir != "" {
+		table["dir"] = a.Dir
+	}
+	if a.Venv != "" {
+		table["venv"] = a.Venv
+	}
+	if len(a.Env) > 0 {
+		table["env"] = a.Env
+	}
+	if a.Run != "" {
+		table["run"] = a.Run
+	}
+	if a.Tmux != "" {
+		table["tmux"] = a.Tmux
+	}
+	return table
+}
+
+func tomlString(key string, value interface{}) (string, error) {
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("alias key '%s' must be a string", key)
+	}
+	return s, nil
+}
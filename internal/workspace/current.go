@@ -0,0 +1,109 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/johnjallday/GoTagManager/config"
+)
+
+// ErrNotInWorkspace is returned by FindEnclosing when the path is not inside
+// a workspace below the root.
+var ErrNotInWorkspace = errors.New("not in a workspace")
+
+// FindEnclosingAll is FindEnclosing for several roots, each searched with
+// its own discovery settings. The workspace is named like ListAll names it,
+// assuming that a root contains a workspace at the same relative path
+// whenever it has a ws_info.toml there.
+func FindEnclosingAll(roots []config.Root, path string) (Workspace, error) {
+	for _, root := range roots {
+		ws, err := FindEnclosing(root.Path, root.Discovery, path)
+		if errors.Is(err, ErrNotInWorkspace) {
+			continue
+		}
+		if err != nil {
+			return Workspace{}, err
+		}
+
+		ws.Root = root.Label
+		for _, other := range roots {
+			if other.Label == root.Label {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(other.Path, filepath.FromSlash(ws.Rel), "ws_info.toml")); err == nil {
+				ws.Name = ws.QualifiedName()
+				break
+			}
+		}
+		return ws, nil
+	}
+	return Workspace{}, ErrNotInWorkspace
+}
+
+// FindEnclosing returns the innermost workspace below root that contains
+// path and that ListWorkspaces would report with the same opts: hidden and
+// excluded directories, levels beyond opts.MaxDepth, and workspaces inside
+// another one unless opts.Nested is set are not considered. Symlinks in both
+// paths are resolved first so that a workspace reached through a link is
+// still recognized.
+func FindEnclosing(root string, opts config.Discovery, path string) (Workspace, error) {
+	root, err := resolvePath(root)
+	if err != nil {
+		return Workspace{}, err
+	}
+	dir, err := resolvePath(path)
+	if err != nil {
+		return Workspace{}, err
+	}
+	if !isBelow(root, dir) {
+		return Workspace{}, ErrNotInWorkspace
+	}
+	if opts.MaxDepth < 1 {
+		opts.MaxDepth = 1
+	}
+
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return Workspace{}, err
+	}
+	d := &discovery{root: root, opts: opts}
+	var found *Workspace
+	child := root
+	for depth, segment := range strings.Split(rel, string(filepath.Separator)) {
+		child = filepath.Join(child, segment)
+		name := d.name(child)
+		if depth >= opts.MaxDepth || segment[0] == '.' || d.excluded(segment, name) {
+			break
+		}
+		if _, err := os.Stat(filepath.Join(child, "ws_info.toml")); err == nil {
+			found = &Workspace{Name: name, Path: child, Rel: name}
+			if !opts.IsNested() {
+				break
+			}
+		} else if !os.IsNotExist(err) {
+			return Workspace{}, err
+		}
+	}
+
+	if found == nil {
+		return Workspace{}, ErrNotInWorkspace
+	}
+	return *found, nil
+}
+
+// resolvePath returns the absolute path with all symlinks resolved.
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
+
+// isBelow reports whether path lies strictly below dir.
+func isBelow(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,107 @@
+package workspace
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/johnjallday/GoTagManager/config"
+)
+
+// makeTree creates the directories below root and puts a ws_info.toml in
+// each of the workspaces.
+func makeTree(t *testing.T, root string, dirs, workspaces []string) {
+	t.Helper()
+	for _, dir := range append(dirs, workspaces...) {
+		if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(dir)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, ws := range workspaces {
+		if err := os.WriteFile(filepath.Join(root, filepath.FromSlash(ws), "ws_info.toml"), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindEnclosing(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root,
+		[]string{"site/src", "node_modules/pkg/lib", "clients/acme/site/inner/deep", "a/b/c/d/e"},
+		[]string{"site", "node_modules/pkg", "clients/acme/site", "clients/acme/site/inner", ".hidden", "a/b/c/d"},
+	)
+	flat := config.Discovery{MaxDepth: 3, Exclude: []string{"node_modules"}}
+	yes := true
+	nested := config.Discovery{MaxDepth: 5, Nested: &yes}
+
+	tests := []struct {
+		name string
+		opts config.Discovery
+		path string
+		want string // "" for ErrNotInWorkspace
+	}{
+		{"workspace itself", flat, "site", "site"},
+		{"inside workspace", flat, "site/src", "site"},
+		{"excluded directory", flat, "node_modules/pkg/lib", ""},
+		{"excluded by path", config.Discovery{MaxDepth: 3, Exclude: []string{"node_modules/*"}}, "node_modules/pkg", ""},
+		{"not nested", flat, "clients/acme/site/inner/deep", "clients/acme/site"},
+		{"nested", nested, "clients/acme/site/inner/deep", "clients/acme/site/inner"},
+		{"nested beyond max depth", config.Discovery{MaxDepth: 3, Nested: &yes}, "clients/acme/site/inner", "clients/acme/site"},
+		{"beyond max depth", flat, "a/b/c/d/e", ""},
+		{"within max depth", config.Discovery{MaxDepth: 4}, "a/b/c/d/e", "a/b/c/d"},
+		{"hidden directory", flat, ".hidden", ""},
+		{"root", flat, ".", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, err := FindEnclosing(root, tt.opts, filepath.Join(root, filepath.FromSlash(tt.path)))
+			if tt.want == "" {
+				if !errors.Is(err, ErrNotInWorkspace) {
+					t.Errorf("got %+v, %v, want ErrNotInWorkspace", ws, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ws.Name != tt.want {
+				t.Errorf("got workspace %q, want %q", ws.Name, tt.want)
+			}
+		})
+	}
+}
+
+// TestFindEnclosingMatchesListWorkspaces checks that every workspace
+// FindEnclosing reports is one ListWorkspaces lists.
+func TestFindEnclosingMatchesListWorkspaces(t *testing.T) {
+	root := t.TempDir()
+	makeTree(t, root,
+		[]string{"node_modules/pkg/lib", "clients/acme/site/inner/deep"},
+		[]string{"node_modules/pkg", "clients/acme/site", "clients/acme/site/inner"},
+	)
+	opts := config.Discovery{MaxDepth: 4, Exclude: []string{"node_modules"}}
+
+	workspaces, err := ListWorkspaces(root, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	listed := make(map[string]bool)
+	for _, ws := range workspaces {
+		listed[ws.Name] = true
+	}
+
+	for _, dir := range []string{"node_modules/pkg/lib", "clients/acme/site/inner/deep", "clients/acme/site"} {
+		ws, err := FindEnclosing(root, opts, filepath.Join(root, filepath.FromSlash(dir)))
+		if errors.Is(err, ErrNotInWorkspace) {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !listed[ws.Name] {
+			t.Errorf("FindEnclosing(%q) = %q, which ListWorkspaces does not list", dir, ws.Name)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/johnjallday/GoTagManager/config"
+)
+
+// ListWorkspaces returns the workspaces below root, sorted by name. It
+// searches up to opts.MaxDepth directory levels deep and, unless opts.Nested
+// is set, does not descend into a workspace once its ws_info.toml is found.
+// Hidden and excluded directories are skipped, and symlinked directories are
+// followed at most once so that link cycles cannot loop.
+func ListWorkspaces(root string, opts config.Discovery) ([]Workspace, error) {
+	d, err := discover(root, opts)
+	if err != nil {
+		return nil, err
+	}
+	return d.workspaces, nil
+}
+
+// ListOrphans returns the directories that ListWorkspaces ignores because
+// they lack a ws_info.toml: every directory below root, outside any
+// workspace, that neither is nor contains a workspace while its parent is
+// the root or contains one. With the default depth of 1 these are the direct
+// children of root without a ws_info.toml.
+func ListOrphans(root string, opts config.Discovery) ([]Workspace, error) {
+	d, err := discover(root, opts)
+	if err != nil {
+		return nil, err
+	}
+	return d.orphans, nil
+}
+
+// ListAll returns the workspaces of every root, sorted by name. Workspaces
+// whose relative paths occur in more than one root are named "label:path".
+func ListAll(roots []config.Root) ([]Workspace, error) {
+	var all []Workspace
+	for _, root := range roots {
+		workspaces, err := ListWorkspaces(root.Path, root.Discovery)
+		if err != nil {
+			return nil, fmt.Errorf("root %s: %w", root.Label, err)
+		}
+		for i := range workspaces {
+			workspaces[i].Root = root.Label
+		}
+		all = append(all, workspaces...)
+	}
+	return qualifyNames(all), nil
+}
+
+// ListAllOrphans returns the orphans of every root (see ListOrphans), named
+// like the workspaces of ListAll.
+func ListAllOrphans(roots []config.Root) ([]Workspace, error) {
+	var all []Workspace
+	for _, root := range roots {
+		orphans, err := ListOrphans(root.Path, root.Discovery)
+		if err != nil {
+			return nil, fmt.Errorf("root %s: %w", root.Label, err)
+		}
+		for i := range orphans {
+			orphans[i].Root = root.Label
+		}
+		all = append(all, orphans...)
+	}
+	return qualifyNames(all), nil
+}
+
+// qualifyNames prefixes the names that occur more than once with their root
+// label and sorts the result by name.
+func qualifyNames(workspaces []Workspace) []Workspace {
+	count := make(map[string]int, len(workspaces))
+	for _, ws := range workspaces {
+		count[ws.Rel]++
+	}
+	for i, ws := range workspaces {
+		if count[ws.Rel] > 1 {
+			workspaces[i].Name = ws.QualifiedName()
+		}
+	}
+	sort.SliceStable(workspaces, func(i, j int) bool { return workspaces[i].Name < workspaces[j].Name })
+	return workspaces
+}
+
+// Find returns the workspace with the given name. "label:name" always
+// selects the workspace in the root with that label. A name that matches
+// no workspace exactly may also be the last element of a single workspace's
+// name, so "site" finds "clients/acme/site" if no other workspace ends in
+// "site".
+func Find(workspaces []Workspace, name string) (Workspace, error) {
+	name = strings.Trim(filepath.ToSlash(name), "/")
+	var byBase []Workspace
+	for _, ws := range workspaces {
+		if ws.Name == name || (ws.Root != "" && ws.QualifiedName() == name) {
+			return ws, nil
+		}
+		if path.Base(ws.Rel) == name {
+			byBase = append(byBase, ws)
+		}
+	}
+
+	switch len(byBase) {
+	case 0:
+		return Workspace{}, fmt.Errorf("workspace '%s' not found", name)
+	case 1:
+		return byBase[0], nil
+	default:
+		names := make([]string, len(byBase))
+		for i, ws := range byBase {
+			names[i] = ws.Name
+		}
+		return Workspace{}, fmt.Errorf("workspace '%s' is ambiguous: %s", name, strings.Join(names, ", "))
+	}
+}
+
+// discovery is the state of one walk below a root.
+type discovery struct {
+	root       string
+	opts       config.Discovery
+	visited    map[string]bool // real paths of directories already walked
+	workspaces []Workspace
+	orphans    []Workspace
+}
+
+func discover(root string, opts config.Discovery) (*discovery, error) {
+	if opts.MaxDepth < 1 {
+		opts.MaxDepth = 1
+	}
+	d := &discovery{root: root, opts: opts, visited: make(map[string]bool)}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+	d.visited[realRoot] = true
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	d.walk(root, entries, 1, true)
+
+	sort.Slice(d.workspaces, func(i, j int) bool { return d.workspaces[i].Name < d.workspaces[j].Name })
+	sort.Slice(d.orphans, func(i, j int) bool { return d.orphans[i].Name < d.orphans[j].Name })
+	return d, nil
+}
+
+// walk scans the entries of dir, whose children are depth levels below the
+// root, and reports whether any of them is or contains a workspace. Children
+// that are neither are recorded as orphans if trackOrphans is set and dir
+// is the root or contains a workspace.
+func (d *discovery) walk(dir string, entries []os.DirEntry, depth int, trackOrphans bool) bool {
+	found := false
+	var empty []Workspace
+
+	for _, entry := range entries {
+		child := filepath.Join(dir, entry.Name())
+		name := d.name(child)
+		if entry.Name()[0] == '.' || d.excluded(entry.Name(), name) || !d.enter(child, entry) {
+			continue
+		}
+
+		var childEntries []os.DirEntry
+		readChildren := func() bool {
+			var err error
+			childEntries, err = os.ReadDir(child)
+			// Unreadable directories are skipped like the files in them
+			return err == nil
+		}
+
+		contains := false
+		if _, err := os.Stat(filepath.Join(child, "ws_info.toml")); err == nil {
+			d.workspaces = append(d.workspaces, Workspace{Name: name, Path: child, Rel: name})
+			contains = true
+			if d.opts.IsNested() && depth < d.opts.MaxDepth && readChildren() {
+				d.walk(child, childEntries, depth+1, false)
+			}
+		} else if depth < d.opts.MaxDepth && readChildren() {
+			contains = d.walk(child, childEntries, depth+1, trackOrphans)
+		}
+
+		if contains {
+			found = true
+		} else {
+			empty = append(empty, Workspace{Name: name, Path: child, Rel: name})
+		}
+	}
+
+	if trackOrphans && (found || dir == d.root) {
+		d.orphans = append(d.orphans, empty...)
+	}
+	return found
+}
+
+// enter reports whether child is a directory, following symlinks, that has
+// not been walked yet, and marks it as walked.
+func (d *discovery) enter(child string, entry os.DirEntry) bool {
+	if !entry.IsDir() {
+		if entry.Type()&os.ModeSymlink == 0 {
+			return false
+		}
+		info, err := os.Stat(child)
+		if err != nil || !info.IsDir() {
+			return false
+		}
+	}
+
+	real, err := filepath.EvalSymlinks(child)
+	if err != nil || d.visited[real] {
+		return false
+	}
+	d.visited[real] = true
+	return true
+}
+
+// excluded reports whether a directory matches an exclude pattern.
+func (d *discovery) excluded(base, name string) bool {
+	for _, pattern := range d.opts.Exclude {
+		target := base
+		if strings.Contains(pattern, "/") {
+			target = name
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// name returns the workspace name of a path below the root.
+func (d *discovery) name(p string) string {
+	rel, err := filepath.Rel(d.root, p)
+	if err != nil {
+		return filepath.Base(p)
+	}
+	return filepath.ToSlash(rel)
+}
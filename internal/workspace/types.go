@@ -1,13 +1,87 @@
 package workspace
 
+import (
+	"fmt"
+	"strings"
+)
+
+// Workspace is a directory below a root that contains a ws_info.toml.
+type Workspace struct {
+	// Name identifies the workspace: its path relative to the root with '/'
+	// separators, e.g. "clients/acme/site". If several roots contain a
+	// workspace with that relative path, Name is qualified with the root's
+	// label, as in "work:clients/acme/site".
+	Name string
+	Path string
+	// Root is the label of the root the workspace was found in.
+	Root string
+	// Rel is the unqualified path relative to the root.
+	Rel string
+}
+
+// QualifiedName returns the name qualified with the root label, which is
+// unique even when Name is not qualified.
+func (w Workspace) QualifiedName() string {
+	if w.Root == "" {
+		return w.Rel
+	}
+	return w.Root + ":" + w.Rel
+}
+
 // WorkspaceInfo represents the structure of ws_info.toml.
 type WorkspaceInfo struct {
 	Accounts map[string]string `toml:"accounts"`
 	Info     InfoSection       `toml:"info"`
+
+	// EffectiveTags is Info.Tags plus every tag implied by the configured
+	// implication rules. It is computed on load and never written to disk.
+	EffectiveTags []string `toml:"-"`
 }
 
 // InfoSection represents the [info] table in ws_info.toml.
 type InfoSection struct {
 	Tags    []string `toml:"tags"`
-	Aliases []string `toml:"aliases"`
+	Aliases []Alias  `toml:"aliases"`
+}
+
+// AliasDefinition is one alias declared in a workspace's ws_info.toml.
+type AliasDefinition struct {
+	Alias         string
+	Entry         Alias // the full alias entry, including its actions
+	WorkspaceName string
+	WorkspacePath string
+	File          string // path of the declaring ws_info.toml
+}
+
+// AliasCollision is an alias declared by more than one workspace.
+type AliasCollision struct {
+	Alias       string
+	Definitions []AliasDefinition
+}
+
+// Alias collision policies, configured as aliases.collision_policy.
+const (
+	CollisionError     = "error"
+	CollisionFirstWins = "first-wins"
+	CollisionSuffix    = "suffix"
+)
+
+// AliasCollisionError is returned under the CollisionError policy.
+type AliasCollisionError struct {
+	Collisions []AliasCollision
+}
+
+func (e *AliasCollisionError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d alias collision(s):", len(e.Collisions))
+	for _, c := range e.Collisions {
+		fmt.Fprintf(&b, "\n  '%s' is declared in", c.Alias)
+		for i, def := range c.Definitions {
+			if i > 0 {
+				b.WriteString(" and")
+			}
+			fmt.Fprintf(&b, " %s", def.File)
+		}
+	}
+	return b.String()
 }
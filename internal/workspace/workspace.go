@@ -2,11 +2,14 @@ package workspace
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/BurntSushi/toml"
+	"github.com/johnjallday/GoTagManager/internal/shell"
 )
 
 // ParseWSInfo parses the ws_info.toml file into a WorkspaceInfo struct.
@@ -28,51 +31,129 @@ func ParseWSInfo(filePath string) (*WorkspaceInfo, error) {
 	return &info, nil
 }
 
-// ListWorkspaces returns a list of workspace directories containing ws_info.toml.
-func ListWorkspaces(root string) ([]string, error) {
-	entries, err := os.ReadDir(root)
-	if err != nil {
-		return nil, err
-	}
+// ListAliases collects all aliases from each workspace's ws_info.toml.
+// It returns a map where the key is the alias name and the value is the workspace name.
+// Aliases declared by more than one workspace are resolved according to policy
+// (see ResolveAliases) and also returned as collisions.
+func ListAliases(workspaces []Workspace, policy string) (map[string]string, []AliasCollision, error) {
+	definitions := CollectAliases(workspaces)
+	return ResolveAliases(definitions, policy)
+}
 
-	var workspaces []string
-	for _, entry := range entries {
-		// Skip hidden directories and ensure the entry is a directory.
-		if entry.Name()[0] == '.' || !entry.IsDir() {
+// CollectAliases returns every alias declared in the workspaces' ws_info.toml
+// files, in workspace order. Files that fail to parse are reported and skipped.
+func CollectAliases(workspaces []Workspace) []AliasDefinition {
+	var definitions []AliasDefinition
+	for _, ws := range workspaces {
+		wsInfoPath := filepath.Join(ws.Path, "ws_info.toml")
+		info, err := ParseWSInfo(wsInfoPath)
+		if err != nil {
+			slog.Warn("failed to parse ws_info.toml", "path", wsInfoPath, "error", err)
 			continue
 		}
 
-		wsInfoPath := filepath.Join(root, entry.Name(), "ws_info.toml")
-		if _, err := os.Stat(wsInfoPath); err == nil {
-			workspaces = append(workspaces, filepath.Join(root, entry.Name()))
+		for _, alias := range info.Info.Aliases {
+			definitions = append(definitions, AliasDefinition{
+				Alias:         alias.Name,
+				Entry:         alias,
+				WorkspaceName: ws.Name,
+				WorkspacePath: ws.Path,
+				File:          wsInfoPath,
+			})
 		}
 	}
-	return workspaces, nil
+	return definitions
 }
 
-// ListAliases collects all aliases from each workspace's ws_info.toml.
-// It returns a map where the key is the alias name and the value is the workspace name.
-func ListAliases(workspaces []string, rootDirectory string) (map[string]string, error) {
-	aliases := make(map[string]string)
+// FindAliasCollisions groups the definitions of every alias declared more
+// than once, sorted by alias name.
+func FindAliasCollisions(definitions []AliasDefinition) []AliasCollision {
+	byAlias := make(map[string][]AliasDefinition)
+	var order []string
+	for _, def := range definitions {
+		if _, seen := byAlias[def.Alias]; !seen {
+			order = append(order, def.Alias)
+		}
+		byAlias[def.Alias] = append(byAlias[def.Alias], def)
+	}
+	sort.Strings(order)
 
-	for _, workspacePath := range workspaces {
-		wsInfoPath := filepath.Join(workspacePath, "ws_info.toml")
-		info, err := ParseWSInfo(wsInfoPath)
-		if err != nil {
-			fmt.Printf("Failed to parse %s: %v\n", wsInfoPath, err)
-			continue
+	var collisions []AliasCollision
+	for _, alias := range order {
+		if defs := byAlias[alias]; len(defs) > 1 {
+			collisions = append(collisions, AliasCollision{Alias: alias, Definitions: defs})
 		}
+	}
+	return collisions
+}
 
-		for _, alias := range info.Info.Aliases {
-			workspaceName := filepath.Base(workspacePath)
-			if _, exists := aliases[alias]; exists {
-				fmt.Printf("Warning: Duplicate alias '%s' found in workspace '%s'. Overwriting previous definition.\n", alias, workspaceName)
+// ResolveAliases maps alias names to workspace names, settling collisions
+// according to policy:
+//
+//   - CollisionError fails with an *AliasCollisionError.
+//   - CollisionFirstWins keeps the first definition in workspace order.
+//   - CollisionSuffix drops the bare alias and gives every colliding
+//     definition a "-<workspace>" suffix instead.
+//
+// The collisions are returned for every policy so callers can report them.
+func ResolveAliases(definitions []AliasDefinition, policy string) (map[string]string, []AliasCollision, error) {
+	resolved, collisions, err := ResolveAliasDefinitions(definitions, policy)
+	if err != nil {
+		return nil, collisions, err
+	}
+
+	aliases := make(map[string]string, len(resolved))
+	for name, def := range resolved {
+		aliases[name] = def.WorkspaceName
+	}
+	return aliases, collisions, nil
+}
+
+// ResolveAliasDefinitions is like ResolveAliases but maps each alias name to
+// its full definition.
+func ResolveAliasDefinitions(definitions []AliasDefinition, policy string) (map[string]AliasDefinition, []AliasCollision, error) {
+	collisions := FindAliasCollisions(definitions)
+	colliding := make(map[string]bool, len(collisions))
+	for _, c := range collisions {
+		colliding[c.Alias] = true
+	}
+
+	aliases := make(map[string]AliasDefinition)
+	switch policy {
+	case CollisionError:
+		if len(collisions) > 0 {
+			return nil, collisions, &AliasCollisionError{Collisions: collisions}
+		}
+		fallthrough
+	case CollisionFirstWins:
+		for _, def := range definitions {
+			if _, exists := aliases[def.Alias]; !exists {
+				aliases[def.Alias] = def
+			}
+		}
+	case CollisionSuffix:
+		for _, def := range definitions {
+			if !colliding[def.Alias] {
+				aliases[def.Alias] = def
+			}
+		}
+		for _, c := range collisions {
+			for _, def := range c.Definitions {
+				name := def.Alias + "-" + shell.SafeAliasName(def.WorkspaceName)
+				for i := 2; ; i++ {
+					if _, exists := aliases[name]; !exists {
+						break
+					}
+					name = fmt.Sprintf("%s-%s-%d", def.Alias, shell.SafeAliasName(def.WorkspaceName), i)
+				}
+				aliases[name] = def
 			}
-			aliases[alias] = workspaceName
 		}
+	default:
+		return nil, collisions, fmt.Errorf("unknown alias collision policy '%s'", policy)
 	}
 
-	return aliases, nil
+	return aliases, collisions, nil
 }
 
 // ListFilesAndDirectories lists all files and directories in the given workspace path.
@@ -110,7 +191,7 @@ func GetWorkspaceSize(workspacePath string) (int64, error) {
 	err := filepath.Walk(workspacePath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			// Skip files or directories that cause errors
-			fmt.Printf("Warning: Skipping '%s' due to error: %v\n", path, err)
+			slog.Warn("skipping path in size calculation", "path", path, "error", err)
 			return nil
 		}
 
@@ -0,0 +1,58 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+	"github.com/johnjallday/GoTagManager/internal/fileutil"
+)
+
+// SaveWSInfo writes info to the ws_info.toml at filePath.
+// Keys in the existing file that WorkspaceInfo does not model are preserved,
+// and the file is replaced atomically so a failed write never leaves a
+// truncated ws_info.toml behind.
+func SaveWSInfo(filePath string, info *WorkspaceInfo) error {
+	raw := make(map[string]interface{})
+	perm := os.FileMode(0644)
+
+	if stat, err := os.Stat(filePath); err == nil {
+		perm = stat.Mode().Perm()
+		if _, err := toml.DecodeFile(filePath, &raw); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", filePath, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	if info.Accounts != nil {
+		raw["accounts"] = info.Accounts
+	}
+
+	infoTable, ok := raw["info"].(map[string]interface{})
+	if !ok {
+		infoTable = make(map[string]interface{})
+	}
+	infoTable["tags"] = nonNil(info.Info.Tags)
+	aliases := make([]interface{}, 0, len(info.Info.Aliases))
+	for _, alias := range info.Info.Aliases {
+		aliases = append(aliases, alias.toTOML())
+	}
+	infoTable["aliases"] = aliases
+	raw["info"] = infoTable
+
+	return fileutil.WriteFileAtomic(filePath, perm, func(file *os.File) error {
+		encoder := toml.NewEncoder(file)
+		encoder.Indent = ""
+		return encoder.Encode(raw)
+	})
+}
+
+// nonNil returns s, or an empty slice if s is nil, so that the TOML encoder
+// writes an empty array instead of dropping the key.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}